@@ -1 +0,0 @@
-12asd
\ No newline at end of file
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/isagarkanojia/go-bookstore/pkg/cache"
+	"github.com/isagarkanojia/go-bookstore/pkg/config"
+	"github.com/isagarkanojia/go-bookstore/pkg/controllers"
+	"github.com/isagarkanojia/go-bookstore/pkg/middleware"
+	"github.com/isagarkanojia/go-bookstore/pkg/models"
+	"github.com/isagarkanojia/go-bookstore/pkg/openapi"
+	"github.com/isagarkanojia/go-bookstore/pkg/routes"
+	"github.com/isagarkanojia/go-bookstore/pkg/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	if err := run(); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg := config.LoadServer()
+	telemetryCfg := config.LoadTelemetry()
+	cacheCfg := config.LoadCache()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: cfg.LogLevel}))
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), telemetryCfg)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
+	config.Connect()
+	if err := models.Init(config.GetDB()); err != nil {
+		return err
+	}
+
+	metrics := telemetry.NewMetrics()
+	if sqlDB, err := config.GetDB().DB(); err == nil {
+		metrics.RegisterDB(sqlDB, "bookstore")
+	}
+
+	if backend, err := newCacheBackend(cacheCfg); err != nil {
+		return err
+	} else if backend != nil {
+		books := cache.NewReadThrough("book:", cacheCfg.TTL, backend, controllers.LoadBook)
+		controllers.SetBookCache(books)
+		metrics.RegisterCache("books", books)
+		logger.Info("book cache enabled", "backend", cacheCfg.Backend, "ttl", cacheCfg.TTL)
+	}
+
+	r := mux.NewRouter()
+	r.Handle(telemetryCfg.MetricsPath, metrics.Handler()).Methods("GET")
+	r.Handle("/openapi.json", openapi.SpecHandler()).Methods("GET")
+	r.Handle("/docs", openapi.DocsHandler("/openapi.json")).Methods("GET")
+	routes.RegisterBookStoreRoutes(r)
+	r.Use(
+		mux.MiddlewareFunc(middleware.Metrics(metrics)),
+		mux.MiddlewareFunc(middleware.Tracing()),
+	)
+
+	handler := middleware.Chain(r,
+		middleware.RequestID(cfg.RequestIDHeader),
+		middleware.Logging(logger),
+		middleware.Recovery(),
+		middleware.CORS(cfg.CORS),
+		middleware.BodyLimit(cfg.MaxBodyBytes),
+	)
+
+	logger.Info("starting server", "addr", cfg.Addr)
+	return http.ListenAndServe(cfg.Addr, handler)
+}
+
+func newCacheBackend(cfg config.Cache) (cache.Backend, error) {
+	switch cfg.Backend {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		return cache.NewLRU(cfg.Size), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		return cache.NewRedis(client, "bookstore:"), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Backend stores opaque values under string keys. Implementations must be
+// safe for concurrent use.
+type Backend interface {
+	// Get returns the value stored under key. The boolean is false when the
+	// key is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl. A ttl of zero means no expiry.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Loader fetches the value for key from the source of truth.
+type Loader[T any] func(ctx context.Context, key string) (T, error)
+
+// ReadThrough serves values from a Backend and falls back to its Loader on
+// a miss. Concurrent misses for the same key share a single load. Values
+// are stored as JSON so any Backend can hold them.
+//
+// A load still in flight when its key is invalidated returns its value but
+// does not store it, since the value may predate the update.
+//
+// Loader errors are returned to the caller and never cached. Backend errors
+// are treated as misses so that an unavailable cache degrades to direct
+// loads instead of failing requests.
+type ReadThrough[T any] struct {
+	backend Backend
+	load    Loader[T]
+	ttl     time.Duration
+	prefix  string
+	group   singleflight.Group
+
+	// flights tracks the keys with loads in progress, so that Invalidate
+	// can make them stale.
+	mu      sync.Mutex
+	flights map[string]*flight
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewReadThrough[T any](prefix string, ttl time.Duration, backend Backend, load Loader[T]) *ReadThrough[T] {
+	return &ReadThrough[T]{
+		backend: backend,
+		load:    load,
+		ttl:     ttl,
+		prefix:  prefix,
+		flights: map[string]*flight{},
+	}
+}
+
+// flight counts the loads of a key in progress. Invalidate bumps its
+// generation, making stale the loads that started before.
+type flight struct {
+	loads      int
+	generation uint64
+}
+
+func (c *ReadThrough[T]) Get(ctx context.Context, key string) (T, error) {
+	k := c.prefix + key
+
+	if b, ok, err := c.backend.Get(ctx, k); err == nil && ok {
+		var v T
+		if err := json.Unmarshal(b, &v); err == nil {
+			c.hits.Add(1)
+			return v, nil
+		}
+	}
+	c.misses.Add(1)
+
+	v, err, _ := c.group.Do(k, func() (interface{}, error) {
+		f, gen := c.startFlight(k)
+		defer c.endFlight(k, f)
+
+		// The load is shared with other callers, so one of them going away
+		// must not cancel it for the rest.
+		v, err := c.load(context.WithoutCancel(ctx), key)
+		if err != nil {
+			return v, err
+		}
+		if b, err := json.Marshal(v); err == nil {
+			c.fill(context.WithoutCancel(ctx), k, f, gen, b)
+		}
+		return v, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// startFlight records a load of k, returning the generation it started at.
+func (c *ReadThrough[T]) startFlight(k string) (*flight, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := c.flights[k]
+	if f == nil {
+		f = &flight{}
+		c.flights[k] = f
+	}
+	f.loads++
+	return f, f.generation
+}
+
+func (c *ReadThrough[T]) endFlight(k string, f *flight) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f.loads--; f.loads == 0 {
+		delete(c.flights, k)
+	}
+}
+
+// current reports whether k was not invalidated since gen.
+func (c *ReadThrough[T]) current(f *flight, gen uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return f.generation == gen
+}
+
+// fill stores b under k unless k was invalidated since gen. The backend is
+// called without the lock, so an invalidation may land between the check
+// and Set, its Delete running first; the generation is checked again after
+// Set to delete the value in that case.
+func (c *ReadThrough[T]) fill(ctx context.Context, k string, f *flight, gen uint64, b []byte) {
+	if !c.current(f, gen) {
+		return
+	}
+
+	c.backend.Set(ctx, k, b, c.ttl)
+
+	if !c.current(f, gen) {
+		c.backend.Delete(ctx, k)
+	}
+}
+
+// Invalidate drops the cached value for key so the next Get reloads it.
+// Loads of key already in progress are not cached.
+func (c *ReadThrough[T]) Invalidate(ctx context.Context, key string) error {
+	k := c.prefix + key
+
+	c.mu.Lock()
+	if f := c.flights[k]; f != nil {
+		f.generation++
+	}
+	c.mu.Unlock()
+
+	c.group.Forget(k)
+	return c.backend.Delete(ctx, k)
+}
+
+func (c *ReadThrough[T]) Hits() uint64 {
+	return c.hits.Load()
+}
+
+func (c *ReadThrough[T]) Misses() uint64 {
+	return c.misses.Load()
+}
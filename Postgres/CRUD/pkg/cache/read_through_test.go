@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type book struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestReadThroughCachesLoadedValues(t *testing.T) {
+	ctx := context.Background()
+	var loads atomic.Int32
+	c := NewReadThrough("book:", time.Minute, NewLRU(10), func(ctx context.Context, key string) (*book, error) {
+		loads.Add(1)
+		return &book{ID: key, Name: "Dune"}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		b, err := c.Get(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Dune", b.Name)
+	}
+
+	assert.EqualValues(t, 1, loads.Load())
+	assert.EqualValues(t, 2, c.Hits())
+	assert.EqualValues(t, 1, c.Misses())
+}
+
+func TestReadThroughInvalidate(t *testing.T) {
+	ctx := context.Background()
+	name := "Dune"
+	c := NewReadThrough("book:", time.Minute, NewLRU(10), func(ctx context.Context, key string) (*book, error) {
+		return &book{ID: key, Name: name}, nil
+	})
+
+	c.Get(ctx, "1")
+	name = "Dune Messiah"
+	assert.NoError(t, c.Invalidate(ctx, "1"))
+
+	b, err := c.Get(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Dune Messiah", b.Name)
+}
+
+func TestReadThroughInvalidateDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	var name atomic.Value
+	name.Store("Dune")
+	loading, release := make(chan struct{}), make(chan struct{})
+	c := NewReadThrough("book:", time.Minute, NewLRU(10), func(ctx context.Context, key string) (*book, error) {
+		b := &book{ID: key, Name: name.Load().(string)}
+		if b.Name == "Dune" {
+			close(loading)
+			<-release
+		}
+		return b, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b, err := c.Get(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Dune", b.Name)
+	}()
+
+	// The update lands while the first load still holds the old row.
+	<-loading
+	name.Store("Dune Messiah")
+	assert.NoError(t, c.Invalidate(ctx, "1"))
+	close(release)
+	<-done
+
+	b, err := c.Get(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Dune Messiah", b.Name)
+}
+
+// blockingSet is a Backend whose Set blocks until release is closed.
+type blockingSet struct {
+	Backend
+	setting chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingSet) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	close(b.setting)
+	<-b.release
+	return b.Backend.Set(ctx, key, value, ttl)
+}
+
+func TestReadThroughSetDoesNotBlockInvalidate(t *testing.T) {
+	ctx := context.Background()
+	backend := &blockingSet{Backend: NewLRU(10), setting: make(chan struct{}), release: make(chan struct{})}
+	c := NewReadThrough("book:", time.Minute, backend, func(ctx context.Context, key string) (*book, error) {
+		return &book{ID: key}, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Get(ctx, "1")
+	}()
+	<-backend.setting
+
+	invalidated := make(chan error, 1)
+	go func() {
+		invalidated <- c.Invalidate(ctx, "2")
+	}()
+
+	select {
+	case err := <-invalidated:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Invalidate waited for the Set of another key")
+	}
+
+	close(backend.release)
+	<-done
+}
+
+func TestReadThroughDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	errNotFound := errors.New("not found")
+	var loads atomic.Int32
+	c := NewReadThrough("book:", time.Minute, NewLRU(10), func(ctx context.Context, key string) (*book, error) {
+		loads.Add(1)
+		return nil, errNotFound
+	})
+
+	_, err := c.Get(ctx, "1")
+	assert.ErrorIs(t, err, errNotFound)
+	_, err = c.Get(ctx, "1")
+	assert.ErrorIs(t, err, errNotFound)
+	assert.EqualValues(t, 2, loads.Load())
+}
+
+func TestReadThroughCollapsesConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+	var loads atomic.Int32
+	release := make(chan struct{})
+	c := NewReadThrough("book:", time.Minute, NewLRU(10), func(ctx context.Context, key string) (*book, error) {
+		loads.Add(1)
+		<-release
+		return &book{ID: key}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Get(ctx, "hot")
+			assert.NoError(t, err)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, loads.Load())
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedis(client, "bookstore:"), mr
+}
+
+func TestRedisStoresUnderPrefix(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+
+	_, ok, err := r.Get(ctx, "book:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, r.Set(ctx, "book:1", []byte(`{"id":"1"}`), 0))
+	v, ok, err := r.Get(ctx, "book:1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`{"id":"1"}`), v)
+	assert.True(t, mr.Exists("bookstore:book:1"))
+
+	require.NoError(t, r.Delete(ctx, "book:1"))
+	_, ok, err = r.Get(ctx, "book:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+
+	require.NoError(t, r.Set(ctx, "book:1", []byte("1"), time.Minute))
+	assert.Equal(t, time.Minute, mr.TTL("bookstore:book:1"))
+
+	mr.FastForward(time.Minute)
+	_, ok, err := r.Get(ctx, "book:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisUnavailable(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+	mr.Close()
+
+	_, ok, err := r.Get(ctx, "book:1")
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
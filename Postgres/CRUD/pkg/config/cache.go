@@ -0,0 +1,32 @@
+package config
+
+import "time"
+
+// Cache holds the settings of the book cache, see LoadCache.
+type Cache struct {
+	Backend       string
+	TTL           time.Duration
+	Size          int
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+}
+
+// LoadCache reads the cache settings from the environment:
+//
+//	CACHE_BACKEND   "none", "memory" or "redis" (default "memory")
+//	CACHE_TTL       lifetime of a cached book (default "5m")
+//	CACHE_SIZE      entries kept by the memory backend (default 10000)
+//	REDIS_ADDR      address of the redis backend (default "localhost:6379")
+//	REDIS_PASSWORD  password of the redis backend
+//	REDIS_DB        database number of the redis backend (default 0)
+func LoadCache() Cache {
+	return Cache{
+		Backend:       getEnv("CACHE_BACKEND", "memory"),
+		TTL:           getEnvDuration("CACHE_TTL", 5*time.Minute),
+		Size:          int(getEnvInt64("CACHE_SIZE", 10000)),
+		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+		RedisDB:       int(getEnvInt64("REDIS_DB", 0)),
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS holds the cross-origin settings applied to every response.
+type CORS struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int
+}
+
+// Server holds the HTTP server settings. Every field can be set from the
+// environment, see LoadServer.
+type Server struct {
+	Addr            string
+	LogLevel        slog.Level
+	RequestIDHeader string
+	MaxBodyBytes    int64
+	CORS            CORS
+}
+
+// LoadServer reads the server settings from the environment:
+//
+//	HTTP_ADDR              listen address (default ":4000")
+//	LOG_LEVEL              debug, info, warn or error (default "info")
+//	REQUEST_ID_HEADER      header carrying the request id (default "X-Request-ID")
+//	MAX_BODY_BYTES         largest accepted request body (default 1 MiB)
+//	CORS_ALLOWED_ORIGINS   comma separated origins, "*" for any (default none)
+//	CORS_ALLOWED_METHODS   comma separated methods
+//	CORS_ALLOWED_HEADERS   comma separated headers
+//	CORS_ALLOW_CREDENTIALS "true" to allow cookies and auth headers
+//	CORS_MAX_AGE           preflight cache duration in seconds
+func LoadServer() Server {
+	return Server{
+		Addr:            getEnv("HTTP_ADDR", ":4000"),
+		LogLevel:        getEnvLevel("LOG_LEVEL", slog.LevelInfo),
+		RequestIDHeader: getEnv("REQUEST_ID_HEADER", "X-Request-ID"),
+		MaxBodyBytes:    getEnvInt64("MAX_BODY_BYTES", 1<<20),
+		CORS: CORS{
+			AllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS", nil),
+			AllowedMethods:   getEnvList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+			AllowedHeaders:   getEnvList("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Request-ID"}),
+			AllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           int(getEnvInt64("CORS_MAX_AGE", 600)),
+		},
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	v, err := strconv.ParseInt(getEnv(key, ""), 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func getEnvFloat64(key string, fallback float64) float64 {
+	v, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func getEnvList(key string, fallback []string) []string {
+	v := getEnv(key, "")
+	if v == "" {
+		return fallback
+	}
+
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvLevel(key string, fallback slog.Level) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(getEnv(key, ""))); err != nil {
+		return fallback
+	}
+	return level
+}
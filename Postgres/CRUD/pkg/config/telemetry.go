@@ -0,0 +1,27 @@
+package config
+
+// Telemetry holds the metrics and tracing settings, see LoadTelemetry.
+type Telemetry struct {
+	ServiceName   string
+	TraceExporter string
+	SampleRatio   float64
+	MetricsPath   string
+}
+
+// LoadTelemetry reads the telemetry settings from the environment:
+//
+//	OTEL_SERVICE_NAME          service name reported on spans (default "bookstore")
+//	OTEL_TRACES_EXPORTER       "none", "stdout" or "otlp" (default "none")
+//	OTEL_TRACES_SAMPLER_RATIO  fraction of traces to sample (default 1)
+//	METRICS_PATH               path serving Prometheus metrics (default "/metrics")
+//
+// The otlp exporter reads its endpoint and headers from the standard
+// OTEL_EXPORTER_OTLP_* variables.
+func LoadTelemetry() Telemetry {
+	return Telemetry{
+		ServiceName:   getEnv("OTEL_SERVICE_NAME", "bookstore"),
+		TraceExporter: getEnv("OTEL_TRACES_EXPORTER", "none"),
+		SampleRatio:   getEnvFloat64("OTEL_TRACES_SAMPLER_RATIO", 1),
+		MetricsPath:   getEnv("METRICS_PATH", "/metrics"),
+	}
+}
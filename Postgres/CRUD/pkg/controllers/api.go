@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/isagarkanojia/go-bookstore/pkg/openapi"
+)
+
+// API implements the server stubs generated from the OpenAPI document by
+// delegating to the handlers of this package.
+type API struct{}
+
+var _ openapi.ServerInterface = API{}
+
+func (API) GetBooks(w http.ResponseWriter, r *http.Request) {
+	GetBooks(w, r)
+}
+
+func (API) CreateBooks(w http.ResponseWriter, r *http.Request) {
+	CreateBooks(w, r)
+}
+
+func (API) GetBookById(w http.ResponseWriter, r *http.Request, _ openapi.BookId) {
+	GetBookById(w, r)
+}
+
+func (API) UpdateBook(w http.ResponseWriter, r *http.Request, _ openapi.BookId) {
+	UpdateBook(w, r)
+}
+
+func (API) DeleteBook(w http.ResponseWriter, r *http.Request, _ openapi.BookId) {
+	DeleteBook(w, r)
+}
+
+func (API) SearchBooks(w http.ResponseWriter, r *http.Request, _ openapi.SearchBooksParams) {
+	SearchBooks(w, r)
+}
@@ -1,22 +1,52 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 
 	"github.com/gorilla/mux"
 
 	"net/http"
 	"strconv"
 
+	"github.com/isagarkanojia/go-bookstore/pkg/cache"
+	"github.com/isagarkanojia/go-bookstore/pkg/middleware"
 	"github.com/isagarkanojia/go-bookstore/pkg/models"
 	"github.com/isagarkanojia/go-bookstore/pkg/utils"
+	"gorm.io/gorm"
 )
 
 var NewBook models.Book
 
+var bookCache *cache.ReadThrough[*models.Book]
+
+// SetBookCache makes GetBookById serve books through c. UpdateBook and
+// DeleteBook invalidate the entries they change.
+func SetBookCache(c *cache.ReadThrough[*models.Book]) {
+	bookCache = c
+}
+
+// LoadBook is the cache.Loader reading a book from the database.
+func LoadBook(ctx context.Context, key string) (*models.Book, error) {
+	ID, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return models.FindBookById(ctx, ID)
+}
+
+func invalidateBook(r *http.Request, bookId string) {
+	if bookCache == nil {
+		return
+	}
+	if err := bookCache.Invalidate(r.Context(), bookId); err != nil {
+		middleware.Logger(r.Context()).Error("failed to invalidate cached book", "book_id", bookId, "error", err)
+	}
+}
+
 func GetBooks(w http.ResponseWriter, r *http.Request) {
-	Books := models.GetAllBooks()
+	Books := models.GetAllBooks(r.Context())
 
 	res, _ := json.Marshal(Books)
 
@@ -31,10 +61,26 @@ func GetBookById(w http.ResponseWriter, r *http.Request) {
 	ID, err := strconv.ParseInt(bookId, 0, 0)
 
 	if err != nil {
-		fmt.Println("error while parsing")
+		middleware.Logger(r.Context()).Warn("invalid book id", "book_id", bookId, "error", err)
+		http.Error(w, "invalid book id", http.StatusBadRequest)
+		return
 	}
 
-	book, _ := models.GetBookById(ID)
+	load := LoadBook
+	if bookCache != nil {
+		load = bookCache.Get
+	}
+
+	book, err := load(r.Context(), strconv.FormatInt(ID, 10))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "book not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		middleware.Logger(r.Context()).Error("failed to load book", "book_id", ID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
 
 	res, _ := json.Marshal(book)
 
@@ -45,9 +91,13 @@ func GetBookById(w http.ResponseWriter, r *http.Request) {
 
 func CreateBooks(w http.ResponseWriter, r *http.Request) {
 	book := models.Book{}
-	utils.ParseBody(r, &book)
+	if err := utils.ParseBody(r, &book); err != nil {
+		middleware.Logger(r.Context()).Warn("invalid book body", "error", err)
+		http.Error(w, "invalid request body", utils.BodyErrorStatus(err))
+		return
+	}
 
-	book.CreateBook()
+	book.CreateBook(r.Context())
 
 	res, _ := json.Marshal(book)
 
@@ -61,10 +111,13 @@ func DeleteBook(w http.ResponseWriter, r *http.Request) {
 	ID, err := strconv.ParseInt(bookId, 0, 0)
 
 	if err != nil {
-		fmt.Println("error while parsing")
+		middleware.Logger(r.Context()).Warn("invalid book id", "book_id", bookId, "error", err)
+		http.Error(w, "invalid book id", http.StatusBadRequest)
+		return
 	}
 
-	book := models.DeleteBook(ID)
+	book := models.DeleteBook(r.Context(), ID)
+	invalidateBook(r, strconv.FormatInt(ID, 10))
 
 	res, _ := json.Marshal(book)
 
@@ -76,17 +129,23 @@ func DeleteBook(w http.ResponseWriter, r *http.Request) {
 
 func UpdateBook(w http.ResponseWriter, r *http.Request) {
 	var updateBook = models.Book{}
-	utils.ParseBody(r, &updateBook)
+	if err := utils.ParseBody(r, &updateBook); err != nil {
+		middleware.Logger(r.Context()).Warn("invalid book body", "error", err)
+		http.Error(w, "invalid request body", utils.BodyErrorStatus(err))
+		return
+	}
 
 	vars := mux.Vars(r)
 	bookId := vars["bookId"]
 	ID, err := strconv.ParseInt(bookId, 0, 0)
 
 	if err != nil {
-		fmt.Println("error while parsing")
+		middleware.Logger(r.Context()).Warn("invalid book id", "book_id", bookId, "error", err)
+		http.Error(w, "invalid book id", http.StatusBadRequest)
+		return
 	}
 
-	book, db := models.GetBookById(ID)
+	book, db := models.GetBookById(r.Context(), ID)
 
 	if updateBook.Name != "" {
 		book.Name = updateBook.Name
@@ -101,6 +160,7 @@ func UpdateBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	db.Save(&book)
+	invalidateBook(r, strconv.FormatInt(ID, 10))
 
 	res, _ := json.Marshal(book)
 
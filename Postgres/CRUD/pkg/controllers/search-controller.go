@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/isagarkanojia/go-bookstore/pkg/middleware"
+	"github.com/isagarkanojia/go-bookstore/pkg/models"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type bookHighlights struct {
+	Name        string `json:"name"`
+	Author      string `json:"author"`
+	Publication string `json:"publication"`
+}
+
+type bookSearchResult struct {
+	Book       models.Book    `json:"book"`
+	Rank       float32        `json:"rank"`
+	Highlights bookHighlights `json:"highlights"`
+}
+
+type bookSearchPage struct {
+	Results []bookSearchResult `json:"results"`
+	Total   int64              `json:"total"`
+	Limit   int                `json:"limit"`
+	Offset  int                `json:"offset"`
+}
+
+func SearchBooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		http.Error(w, "missing search query", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := queryInt(query.Get("limit"), defaultSearchLimit)
+	if err != nil || limit < 1 || limit > maxSearchLimit {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	offset, err := queryInt(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	found, total, err := models.SearchBooks(r.Context(), q, limit, offset)
+	if err != nil {
+		middleware.Logger(r.Context()).Error("failed to search books", "query", q, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	page := bookSearchPage{
+		Results: make([]bookSearchResult, 0, len(found)),
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for _, f := range found {
+		page.Results = append(page.Results, bookSearchResult{
+			Book: f.Book,
+			Rank: f.Rank,
+			Highlights: bookHighlights{
+				Name:        f.NameHeadline,
+				Author:      f.AuthorHeadline,
+				Publication: f.PublicationHeadline,
+			},
+		})
+	}
+
+	res, _ := json.Marshal(page)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(res)
+}
+
+func queryInt(v string, fallback int) (int, error) {
+	if v == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import "net/http"
+
+// BodyLimit caps the request body at limit bytes. Reading past the limit
+// fails with *http.MaxBytesError. A limit of zero or less disables the cap.
+func BodyLimit(limit int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/isagarkanojia/go-bookstore/pkg/telemetry"
+)
+
+// Metrics records request counts and latencies labelled by the matched
+// route template, so it has to be installed with mux.Router.Use.
+func Metrics(m *telemetry.Metrics) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			route := routeTemplate(r)
+			m.Requests.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+			m.Duration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route, e.g.
+// "/books/{bookId}", so that ids do not blow up label cardinality.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return "unmatched"
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import "net/http"
+
+// Middleware wraps an http.Handler with extra behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with mws so that the first middleware is the outermost one
+// and sees the request first.
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
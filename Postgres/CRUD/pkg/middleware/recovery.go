@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// Recovery turns a panicking handler into a 500 response instead of letting
+// it tear down the connection.
+func Recovery() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				Logger(r.Context()).Error("panic recovered",
+					"error", err,
+					"stack", string(debug.Stack()),
+				)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package models
 
 import (
-	"github.com/isagarkanojia/go-bookstore/pkg/config"
+	"context"
+
+	"github.com/isagarkanojia/go-bookstore/pkg/telemetry"
 	"gorm.io/gorm"
 )
 
@@ -14,31 +16,48 @@ type Book struct {
 	Publication string `json:"publication"`
 }
 
-func init() {
-	config.Connect()
-	db = config.GetDB()
-	db.AutoMigrate(&Book{})
+// Init points the models at d, installs the tracing plugin and migrates the
+// schema. It has to be called before any other function of the package.
+func Init(d *gorm.DB) error {
+	if err := d.Use(telemetry.GormPlugin{}); err != nil {
+		return err
+	}
+	db = d
+	if err := db.AutoMigrate(&Book{}); err != nil {
+		return err
+	}
+	return migrateSearch(db)
 }
 
-func (b *Book) CreateBook() *Book {
-	db.Create(&b)
+func (b *Book) CreateBook(ctx context.Context) *Book {
+	db.WithContext(ctx).Create(&b)
 	return b
 }
 
-func GetAllBooks() []Book {
+func GetAllBooks(ctx context.Context) []Book {
 	var Books []Book
-	db.Find(&Books)
+	db.WithContext(ctx).Find(&Books)
 	return Books
 }
 
-func GetBookById(Id int64) (*Book, *gorm.DB) {
+func GetBookById(ctx context.Context, Id int64) (*Book, *gorm.DB) {
 	var getBook Book
-	db := db.Where("ID=?", Id).Find(&getBook)
+	db := db.WithContext(ctx).Where("ID=?", Id).Find(&getBook)
 	return &getBook, db
 }
 
-func DeleteBook(Id int64) Book {
+// FindBookById is like GetBookById but reports a missing book as
+// gorm.ErrRecordNotFound.
+func FindBookById(ctx context.Context, Id int64) (*Book, error) {
+	var book Book
+	if err := db.WithContext(ctx).Where("ID=?", Id).First(&book).Error; err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+func DeleteBook(ctx context.Context, Id int64) Book {
 	var book Book
-	db.Where("ID=?", Id).Delete(&book)
+	db.WithContext(ctx).Where("ID=?", Id).Delete(&book)
 	return book
 }
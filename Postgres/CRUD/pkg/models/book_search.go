@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrSearchUnsupported is returned by SearchBooks when the database is not
+// Postgres.
+var ErrSearchUnsupported = errors.New("full-text search requires postgres")
+
+// searchMigrations add the generated tsvector column searched by SearchBooks
+// and its GIN index. Names weigh more than authors, authors more than
+// publications.
+var searchMigrations = []string{
+	`ALTER TABLE books ADD COLUMN IF NOT EXISTS search tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(author, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(publication, '')), 'C')
+		) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_books_search ON books USING GIN (search)`,
+}
+
+const searchQuery = `
+SELECT books.id, books.created_at, books.updated_at, books.deleted_at,
+	books.name, books.author, books.publication,
+	ts_rank(books.search, q) AS rank,
+	ts_headline('english', books.name, q, 'HighlightAll=true') AS name_headline,
+	ts_headline('english', books.author, q, 'HighlightAll=true') AS author_headline,
+	ts_headline('english', books.publication, q, 'HighlightAll=true') AS publication_headline
+FROM books, websearch_to_tsquery('english', @query) AS q
+WHERE books.deleted_at IS NULL AND books.search @@ q
+ORDER BY rank DESC, books.id
+LIMIT @limit OFFSET @offset`
+
+const searchCountQuery = `
+SELECT count(*)
+FROM books, websearch_to_tsquery('english', @query) AS q
+WHERE books.deleted_at IS NULL AND books.search @@ q`
+
+// BookSearchResult is a book matched by SearchBooks. The headlines hold the
+// fields with the matching words wrapped in <b> tags.
+type BookSearchResult struct {
+	Book
+	Rank                float32
+	NameHeadline        string
+	AuthorHeadline      string
+	PublicationHeadline string
+}
+
+func migrateSearch(d *gorm.DB) error {
+	if d.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, stmt := range searchMigrations {
+		if err := d.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchBooks returns one page of the books matching query, best match
+// first, together with the total number of matches.
+func SearchBooks(ctx context.Context, query string, limit, offset int) ([]BookSearchResult, int64, error) {
+	if db.Dialector.Name() != "postgres" {
+		return nil, 0, ErrSearchUnsupported
+	}
+
+	args := map[string]interface{}{
+		"query":  query,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	var total int64
+	if err := db.WithContext(ctx).Raw(searchCountQuery, args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := []BookSearchResult{}
+	if total == 0 {
+		return results, 0, nil
+	}
+	if err := db.WithContext(ctx).Raw(searchQuery, args).Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
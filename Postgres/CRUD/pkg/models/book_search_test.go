@@ -0,0 +1,55 @@
+package models
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestSearchBooks needs a scratch Postgres database, e.g.
+//
+//	BOOKSTORE_TEST_DSN="host=localhost user=postgres password=1 dbname=bookstore_test sslmode=disable" go test ./pkg/models
+func TestSearchBooks(t *testing.T) {
+	dsn := os.Getenv("BOOKSTORE_TEST_DSN")
+	if dsn == "" {
+		t.Skip("BOOKSTORE_TEST_DSN is not set")
+	}
+
+	d, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, d.Migrator().DropTable(&Book{}))
+	require.NoError(t, Init(d))
+
+	ctx := context.Background()
+	for _, b := range []Book{
+		{Name: "Dune", Author: "Frank Herbert", Publication: "Chilton Books"},
+		{Name: "Children of Dune", Author: "Frank Herbert", Publication: "Putnam"},
+		{Name: "The Left Hand of Darkness", Author: "Ursula K. Le Guin", Publication: "Ace Books"},
+	} {
+		b := b
+		b.CreateBook(ctx)
+	}
+
+	results, total, err := SearchBooks(ctx, "dune", 10, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Dune", results[0].Name)
+	assert.Equal(t, "<b>Dune</b>", results[0].NameHeadline)
+
+	results, total, err = SearchBooks(ctx, `herbert -children`, 10, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Equal(t, "Dune", results[0].Name)
+
+	results, total, err = SearchBooks(ctx, "books", 1, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Len(t, results, 1)
+}
@@ -0,0 +1,5 @@
+// Package openapi holds the OpenAPI 3 document of the bookstore API and the
+// server stubs generated from it. Edit openapi.yaml, then run go generate.
+package openapi
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.3.0 -config config.yaml openapi.yaml
@@ -1,15 +1,22 @@
 package routes
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 	"github.com/isagarkanojia/go-bookstore/pkg/controllers"
+	"github.com/isagarkanojia/go-bookstore/pkg/middleware"
+	"github.com/isagarkanojia/go-bookstore/pkg/openapi"
 )
 
+// RegisterBookStoreRoutes mounts the routes described by the OpenAPI
+// document, see pkg/openapi/openapi.yaml.
 var RegisterBookStoreRoutes = func(router *mux.Router) {
-	router.HandleFunc("/books", controllers.CreateBooks).Methods("POST")
-	router.HandleFunc("/books", controllers.GetBooks).Methods("GET")
-	router.HandleFunc("/books/{bookId}", controllers.GetBookById).Methods("GET")
-	router.HandleFunc("/books/{bookId}", controllers.UpdateBook).Methods("PUT")
-	router.HandleFunc("/books/{bookId}", controllers.DeleteBook).Methods("DELETE")
-
+	openapi.HandlerWithOptions(controllers.API{}, openapi.GorillaServerOptions{
+		BaseRouter: router,
+		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			middleware.Logger(r.Context()).Warn("invalid request parameter", "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+	})
 }
@@ -0,0 +1,142 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+	"github.com/isagarkanojia/go-bookstore/pkg/middleware"
+	"github.com/isagarkanojia/go-bookstore/pkg/models"
+	"github.com/isagarkanojia/go-bookstore/pkg/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// specClient sends requests to the bookstore routes and checks every
+// response against the OpenAPI document.
+type specClient struct {
+	t       *testing.T
+	handler http.Handler
+	router  routers.Router
+}
+
+func newSpecClient(t *testing.T) *specClient {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, models.Init(db))
+
+	spec, err := openapi.GetSwagger()
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate(context.Background()))
+	// Validate against the paths alone, whatever the servers list says.
+	spec.Servers = nil
+
+	router, err := gorillamux.NewRouter(spec)
+	require.NoError(t, err)
+
+	r := mux.NewRouter()
+	RegisterBookStoreRoutes(r)
+
+	return &specClient{
+		t:       t,
+		handler: middleware.Chain(r, middleware.BodyLimit(1024)),
+		router:  router,
+	}
+}
+
+func (c *specClient) do(method, target string, body []byte) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+
+	route, params, err := c.router.FindRoute(httptest.NewRequest(method, target, nil))
+	require.NoError(c.t, err, "%s %s is not in the spec", method, target)
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: params,
+			Route:      route,
+		},
+		Status: rec.Code,
+		Header: rec.Header(),
+		Body:   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+	})
+	assert.NoError(c.t, err, "%s %s returned %d %s", method, target, rec.Code, rec.Body.String())
+
+	return rec
+}
+
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	c := newSpecClient(t)
+
+	rec := c.do(http.MethodGet, "/books", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = c.do(http.MethodPost, "/books", []byte(`{"name":"Dune","author":"Frank Herbert","publication":"Chilton"}`))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var created models.Book
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.NotZero(t, created.ID)
+
+	rec = c.do(http.MethodGet, "/books", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = c.do(http.MethodGet, "/books/1", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"Dune"`)
+
+	rec = c.do(http.MethodPut, "/books/1", []byte(`{"publication":"Ace"}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"publication":"Ace"`)
+
+	rec = c.do(http.MethodDelete, "/books/1", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = c.do(http.MethodGet, "/books/1", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRouteErrorsMatchOpenAPISpec(t *testing.T) {
+	c := newSpecClient(t)
+
+	rec := c.do(http.MethodGet, "/books/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = c.do(http.MethodPost, "/books", []byte(`{"name":`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = c.do(http.MethodPost, "/books", []byte(`{"name":"`+strings.Repeat("a", 2048)+`"}`))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestSearchErrorsMatchOpenAPISpec(t *testing.T) {
+	c := newSpecClient(t)
+
+	rec := c.do(http.MethodGet, "/books/search", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = c.do(http.MethodGet, "/books/search?q=dune&limit=500", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = c.do(http.MethodGet, "/books/search?q=dune&offset=-1", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,92 @@
+package telemetry
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	gormBefore  = "telemetry:before"
+	gormAfter   = "telemetry:after"
+	gormSpanKey = "telemetry:span"
+)
+
+// GormPlugin opens a client span around every GORM operation. Register it
+// with db.Use and pass the request context through db.WithContext so the
+// query spans are parented to the HTTP span.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "telemetry:tracing"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	errs := []error{
+		cb.Create().Before("gorm:create").Register(gormBefore, p.before("create")),
+		cb.Create().After("gorm:create").Register(gormAfter, p.after),
+		cb.Query().Before("gorm:query").Register(gormBefore, p.before("query")),
+		cb.Query().After("gorm:query").Register(gormAfter, p.after),
+		cb.Update().Before("gorm:update").Register(gormBefore, p.before("update")),
+		cb.Update().After("gorm:update").Register(gormAfter, p.after),
+		cb.Delete().Before("gorm:delete").Register(gormBefore, p.before("delete")),
+		cb.Delete().After("gorm:delete").Register(gormAfter, p.after),
+		cb.Row().Before("gorm:row").Register(gormBefore, p.before("row")),
+		cb.Row().After("gorm:row").Register(gormAfter, p.after),
+		cb.Raw().Before("gorm:raw").Register(gormBefore, p.before("raw")),
+		cb.Raw().After("gorm:raw").Register(gormAfter, p.after),
+	}
+	return errors.Join(errs...)
+}
+
+func (GormPlugin) before(op string) func(*gorm.DB) {
+	tracer := otel.Tracer(instrumentationName)
+
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+
+		name := "gorm." + op
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+
+		ctx, span := tracer.Start(db.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperation(op),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func (GormPlugin) after(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := v.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBStatement(db.Statement.SQL.String()),
+		semconv.DBSQLTable(db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics owns the Prometheus registry of the bookstore and the HTTP
+// collectors recorded by the middleware.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	Requests *prometheus.CounterVec
+	Duration *prometheus.HistogramVec
+}
+
+func NewMetrics() *Metrics {
+	reg := prometheus.NewRegistry()
+
+	m := &Metrics{
+		Registry: reg,
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "bookstore",
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests handled, by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "bookstore",
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency, by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+	}
+
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.Requests,
+		m.Duration,
+	)
+	return m
+}
+
+// RegisterDB exports the connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// CacheStats is implemented by caches that count their hits and misses.
+type CacheStats interface {
+	Hits() uint64
+	Misses() uint64
+}
+
+// RegisterCache exports the hit and miss counters of the cache called name.
+func (m *Metrics) RegisterCache(name string, stats CacheStats) {
+	opts := func(result string) prometheus.CounterOpts {
+		return prometheus.CounterOpts{
+			Namespace:   "bookstore",
+			Subsystem:   "cache",
+			Name:        "requests_total",
+			Help:        "Cache lookups, by cache and result.",
+			ConstLabels: prometheus.Labels{"cache": name, "result": result},
+		}
+	}
+
+	m.Registry.MustRegister(
+		prometheus.NewCounterFunc(opts("hit"), func() float64 { return float64(stats.Hits()) }),
+		prometheus.NewCounterFunc(opts("miss"), func() float64 { return float64(stats.Misses()) }),
+	)
+}
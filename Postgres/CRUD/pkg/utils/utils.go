@@ -2,19 +2,29 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
-	"log"
 	"net/http"
 )
 
-func ParseBody(r *http.Request, x interface{}) {
+func ParseBody(r *http.Request, x interface{}) error {
 	defer r.Body.Close()
 
 	body, err := ioutil.ReadAll(r.Body)
 
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
 
-	json.Unmarshal(body, x)
+	return json.Unmarshal(body, x)
+}
+
+// BodyErrorStatus maps an error returned by ParseBody to the HTTP status
+// that should be sent back to the client.
+func BodyErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
 }
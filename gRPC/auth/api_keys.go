@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// APIKeys authenticates static API keys. Keys are only kept hashed in
+// memory.
+type APIKeys struct {
+	keys map[[sha256.Size]byte]Identity
+}
+
+// LoadAPIKeys reads the API keys from the file at path, see ParseAPIKeys.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseAPIKeys(f)
+}
+
+// ParseAPIKeys reads one key per line in the form
+//
+//	<key> <subject> [scope ...]
+//
+// Blank lines and lines starting with '#' are ignored.
+func ParseAPIKeys(r io.Reader) (*APIKeys, error) {
+	a := &APIKeys{keys: map[[sha256.Size]byte]Identity{}}
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: want <key> <subject> [scope ...]", n)
+		}
+
+		sum := sha256.Sum256([]byte(fields[0]))
+		if _, ok := a.keys[sum]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key", n)
+		}
+		a.keys[sum] = Identity{Subject: fields[1], Scopes: fields[2:]}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *APIKeys) Authenticate(_ context.Context, token string) (Identity, error) {
+	id, ok := a.keys[sha256.Sum256([]byte(token))]
+	if !ok {
+		return Identity{}, fmt.Errorf("%w: unknown API key", ErrInvalidToken)
+	}
+	return id, nil
+}
@@ -0,0 +1,83 @@
+// Package auth authenticates gRPC callers from a bearer token carried in
+// the "authorization" metadata and authorizes them against a per-method
+// policy. Tokens are either HMAC signed JWTs or static API keys.
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrInvalidToken is returned by an Authenticator rejecting a token.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Identity is the authenticated caller of an RPC.
+type Identity struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasScope reports whether the identity was granted scope.
+func (id Identity) HasScope(scope string) bool {
+	for _, s := range id.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator turns a bearer token into the identity it was issued to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Identity, error)
+}
+
+type identityKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity of the caller, set by the server
+// interceptors once the caller has been authenticated.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// bearerToken extracts the token from the "authorization: Bearer <token>"
+// incoming metadata.
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) != 1 {
+		return "", false
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// Any accepts the tokens accepted by any of the authenticators, tried in
+// order.
+type Any []Authenticator
+
+func (a Any) Authenticate(ctx context.Context, token string) (Identity, error) {
+	err := ErrInvalidToken
+	for _, auth := range a {
+		var id Identity
+		if id, err = auth.Authenticate(ctx, token); err == nil {
+			return id, nil
+		}
+	}
+	return Identity{}, err
+}
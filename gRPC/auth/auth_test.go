@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWT(t *testing.T) {
+	secret := []byte("s3cret")
+	a, err := NewJWT(secret, "greet")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	token, err := SignJWT(secret, "greet", Identity{Subject: "ngoc", Scopes: []string{"a", "b"}}, time.Minute)
+	require.NoError(t, err)
+	id, err := a.Authenticate(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, Identity{Subject: "ngoc", Scopes: []string{"a", "b"}}, id)
+
+	for name, sign := range map[string]func() (string, error){
+		"wrong secret": func() (string, error) {
+			return SignJWT([]byte("other"), "greet", Identity{Subject: "ngoc"}, time.Minute)
+		},
+		"wrong issuer": func() (string, error) {
+			return SignJWT(secret, "other", Identity{Subject: "ngoc"}, time.Minute)
+		},
+		"expired": func() (string, error) {
+			return SignJWT(secret, "greet", Identity{Subject: "ngoc"}, -time.Hour)
+		},
+		"no subject": func() (string, error) {
+			return SignJWT(secret, "greet", Identity{}, time.Minute)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, err := sign()
+			require.NoError(t, err)
+			_, err = a.Authenticate(ctx, token)
+			assert.True(t, errors.Is(err, ErrInvalidToken), err)
+		})
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	a, err := ParseAPIKeys(strings.NewReader("# comment\n\nk1 alice greet:everyone\nk2 bob\n"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	id, err := a.Authenticate(ctx, "k1")
+	require.NoError(t, err)
+	assert.Equal(t, Identity{Subject: "alice", Scopes: []string{"greet:everyone"}}, id)
+
+	id, err = a.Authenticate(ctx, "k2")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", id.Subject)
+	assert.False(t, id.HasScope("greet:everyone"))
+
+	_, err = a.Authenticate(ctx, "k3")
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+
+	_, err = ParseAPIKeys(strings.NewReader("k1 alice\nk1 bob\n"))
+	assert.ErrorContains(t, err, "line 2: duplicate key")
+	_, err = ParseAPIKeys(strings.NewReader("k1\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestPolicyRule(t *testing.T) {
+	p := Policy{
+		"/pkg.S/*": {},
+		"/pkg.S/M": {Scopes: []string{"m"}},
+	}
+
+	r, ok := p.rule("/pkg.S/M")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"m"}, r.Scopes)
+
+	r, ok = p.rule("/pkg.S/Other")
+	assert.True(t, ok)
+	assert.Empty(t, r.Scopes)
+
+	_, ok = p.rule("/pkg.T/M")
+	assert.False(t, ok)
+}
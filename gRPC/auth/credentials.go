@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// TokenCredentials attach a bearer token to every RPC of a connection.
+type TokenCredentials struct {
+	Token string
+	// Insecure allows sending the token over a connection without
+	// transport security, which should only be done in tests.
+	Insecure bool
+}
+
+var _ credentials.PerRPCCredentials = TokenCredentials{}
+
+// TokenFromFile returns the credentials for the token stored in the file
+// at path.
+func TokenFromFile(path string) (TokenCredentials, error) {
+	b, err := os.ReadFile(path)
+
+	if err != nil {
+		return TokenCredentials{}, fmt.Errorf("reading token: %w", err)
+	}
+	return TokenCredentials{Token: strings.TrimSpace(string(b))}, nil
+}
+
+func (c TokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.Token}, nil
+}
+
+func (c TokenCredentials) RequireTransportSecurity() bool {
+	return !c.Insecure
+}
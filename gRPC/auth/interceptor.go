@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor authenticates and authorizes every unary call
+// against policy. The caller identity is available to the handler through
+// FromContext.
+func UnaryServerInterceptor(a Authenticator, policy Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, a, policy, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates and authorizes every stream
+// against policy. The caller identity is available to the handler through
+// FromContext on the stream context.
+func StreamServerInterceptor(a Authenticator, policy Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), a, policy, info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+		return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize returns Unauthenticated when the caller has no valid token and
+// PermissionDenied when it is not allowed to call method.
+func authorize(ctx context.Context, a Authenticator, policy Policy, method string) (context.Context, error) {
+	rule, ok := policy.rule(method)
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed", method)
+	}
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		if rule.Public {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	id, err := a.Authenticate(ctx, token)
+
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if scope, missing := rule.missingScope(id); missing {
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires the %q scope", method, scope)
+	}
+	return NewContext(ctx, id), nil
+}
+
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
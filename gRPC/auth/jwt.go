@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWT authenticates HS256 signed JSON Web Tokens. The subject comes from
+// the "sub" claim and the scopes from the space separated "scope" claim.
+type JWT struct {
+	secret []byte
+	opts   []jwt.ParserOption
+}
+
+type claims struct {
+	Scope string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// NewJWT returns an authenticator accepting the tokens signed with secret.
+// When issuer is not empty, the "iss" claim must match it.
+func NewJWT(secret []byte, issuer string) (*JWT, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("empty JWT secret")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	return &JWT{secret: secret, opts: opts}, nil
+}
+
+func (a *JWT) Authenticate(_ context.Context, token string) (Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return a.secret, nil
+	}, a.opts...)
+
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if c.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	return Identity{Subject: c.Subject, Scopes: strings.Fields(c.Scope)}, nil
+}
+
+// SignJWT issues a token for id valid for ttl, signed with secret.
+func SignJWT(secret []byte, issuer string, id Identity, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Scope: strings.Join(id.Scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   id.Subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	})
+	return token.SignedString(secret)
+}
@@ -0,0 +1,40 @@
+package auth
+
+import "strings"
+
+// Rule is what a caller needs to invoke a method.
+type Rule struct {
+	// Public methods can be called without a token.
+	Public bool
+	// Scopes all have to be granted to the caller.
+	Scopes []string
+}
+
+// Policy maps full method names, like "/greet.GreetService/Greet", to the
+// rule guarding them. A "/package.Service/*" entry covers every method of
+// the service that has no entry of its own. Methods covered by no entry are
+// denied.
+type Policy map[string]Rule
+
+// rule returns the rule guarding method.
+func (p Policy) rule(method string) (Rule, bool) {
+	if r, ok := p[method]; ok {
+		return r, true
+	}
+
+	if i := strings.LastIndexByte(method, '/'); i >= 0 {
+		r, ok := p[method[:i+1]+"*"]
+		return r, ok
+	}
+	return Rule{}, false
+}
+
+// missingScope returns the first scope of r that id was not granted.
+func (r Rule) missingScope(id Identity) (string, bool) {
+	for _, s := range r.Scopes {
+		if !id.HasScope(s) {
+			return s, true
+		}
+	}
+	return "", false
+}
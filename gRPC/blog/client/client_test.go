@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeBlog holds a single blog with the id "1", and records the update
+// masks it receives.
+type fakeBlog struct {
+	pb.UnimplementedBlogServiceServer
+	masks [][]string
+}
+
+var fakeTime = timestamppb.New(time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC))
+
+func (f *fakeBlog) find(id string) (*pb.Blog, error) {
+	if id != "1" {
+		return nil, status.Errorf(codes.NotFound, "blog %q not found", id)
+	}
+	return &pb.Blog{Id: "1", AuthorId: "ngoc", Title: "Hello", Content: "World", UpdateTime: fakeTime}, nil
+}
+
+func (f *fakeBlog) CreateBlog(ctx context.Context, in *pb.CreateBlogRequest) (*pb.Blog, error) {
+	b := in.Blog
+	b.Id = "2"
+	return b, nil
+}
+
+func (f *fakeBlog) ReadBlog(ctx context.Context, in *pb.ReadBlogRequest) (*pb.Blog, error) {
+	return f.find(in.Id)
+}
+
+func (f *fakeBlog) UpdateBlog(ctx context.Context, in *pb.UpdateBlogRequest) (*pb.Blog, error) {
+	b, err := f.find(in.Blog.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	f.masks = append(f.masks, in.UpdateMask.Paths)
+	for _, path := range in.UpdateMask.Paths {
+		switch path {
+		case "author_id":
+			b.AuthorId = in.Blog.AuthorId
+		case "title":
+			b.Title = in.Blog.Title
+		case "content":
+			b.Content = in.Blog.Content
+		}
+	}
+	return b, nil
+}
+
+func (f *fakeBlog) DeleteBlog(ctx context.Context, in *pb.DeleteBlogRequest) (*emptypb.Empty, error) {
+	_, err := f.find(in.Id)
+	return &emptypb.Empty{}, err
+}
+
+func (f *fakeBlog) ListBlogs(in *pb.ListBlogsRequest, stream pb.BlogService_ListBlogsServer) error {
+	for _, b := range []*pb.Blog{{Id: "1", AuthorId: "ngoc", Title: "Hello"}, {Id: "3", AuthorId: "clement", Title: "Bye"}} {
+		if in.AuthorId != "" && in.AuthorId != b.AuthorId {
+			continue
+		}
+
+		if err := stream.Send(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dialFake(t *testing.T, f *fakeBlog) pb.BlogServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterBlogServiceServer(s, f)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewBlogServiceClient(conn)
+}
+
+func TestCommands(t *testing.T) {
+	f := &fakeBlog{}
+	c := dialFake(t, f)
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"create", "ngoc", "Hello"}, "2"},
+		{[]string{"read", "1"}, "id:      1\nauthor:  ngoc\ntitle:   Hello\nupdated: 2024-05-01T08:30:00Z\n\nWorld"},
+		{[]string{"update", "-title", "Hi", "-content", "", "1"}, "id:      1\nauthor:  ngoc\ntitle:   Hi\nupdated: 2024-05-01T08:30:00Z"},
+		{[]string{"update", "-author", "clement", "1"}, "id:      1\nauthor:  clement\ntitle:   Hello\nupdated: 2024-05-01T08:30:00Z\n\nWorld"},
+		{[]string{"delete", "1"}, ""},
+		{[]string{"list"}, "1\tngoc\tHello\n3\tclement\tBye"},
+		{[]string{"list", "clement"}, "3\tclement\tBye"},
+	} {
+		line := strings.Join(tc.args, " ")
+		cmd, err := lookupCommand(tc.args[0])
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, cmd.run(c, tc.args[1:], &out), line)
+		assert.Equal(t, tc.want, strings.TrimSpace(out.String()), line)
+	}
+	assert.Equal(t, [][]string{{"content", "title"}, {"author_id"}}, f.masks)
+}
+
+func TestCommandErrors(t *testing.T) {
+	c := dialFake(t, &fakeBlog{})
+
+	for _, line := range []string{"create ngoc", "read", "update 1", "update -title", "update -color red 1", "delete", "list a b"} {
+		args := strings.Fields(line)
+		cmd, err := lookupCommand(args[0])
+		require.NoError(t, err)
+		assert.Error(t, cmd.run(c, args[1:], io.Discard), line)
+	}
+
+	err := commands["read"].run(c, []string{"2"}, io.Discard)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = lookupCommand("publish")
+	assert.Error(t, err)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+)
+
+type command struct {
+	usage string
+	run   func(c pb.BlogServiceClient, args []string, out io.Writer) error
+}
+
+var commands = map[string]command{
+	"create": {
+		usage: "<author> <title> [content] create a blog with CreateBlog and print its id",
+		run: func(c pb.BlogServiceClient, args []string, out io.Writer) error {
+			if len(args) < 2 || len(args) > 3 {
+				return fmt.Errorf("wrong number of arguments: %d", len(args))
+			}
+
+			blog := &pb.Blog{AuthorId: args[0], Title: args[1]}
+			if len(args) == 3 {
+				blog.Content = args[2]
+			}
+
+			blog, err := doCreate(context.Background(), c, blog)
+
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, blog.Id)
+			return nil
+		},
+	},
+	"read": {
+		usage: "<id> print a blog with ReadBlog",
+		run: func(c pb.BlogServiceClient, args []string, out io.Writer) error {
+			if len(args) != 1 {
+				return fmt.Errorf("want 1 id, got %d", len(args))
+			}
+
+			blog, err := doRead(context.Background(), c, args[0])
+
+			if err != nil {
+				return err
+			}
+			printBlog(out, blog)
+			return nil
+		},
+	},
+	"update": {
+		usage: "[-author a] [-title t] [-content c] <id> change the given fields of a blog with UpdateBlog",
+		run: func(c pb.BlogServiceClient, args []string, out io.Writer) error {
+			blog := &pb.Blog{}
+
+			fs := flag.NewFlagSet("update", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.StringVar(&blog.AuthorId, "author", "", "new author")
+			fs.StringVar(&blog.Title, "title", "", "new title")
+			fs.StringVar(&blog.Content, "content", "", "new content, may be empty")
+
+			if err := fs.Parse(args); err != nil {
+				return err
+			}
+
+			if fs.NArg() != 1 {
+				return fmt.Errorf("want 1 id, got %d", fs.NArg())
+			}
+			blog.Id = fs.Arg(0)
+
+			// Only the flags given go in the mask, so that -content "" empties
+			// the content while leaving out -content keeps it.
+			var paths []string
+			fs.Visit(func(f *flag.Flag) {
+				paths = append(paths, updateFields[f.Name])
+			})
+
+			if len(paths) == 0 {
+				return fmt.Errorf("nothing to update")
+			}
+
+			blog, err := doUpdate(context.Background(), c, blog, paths)
+
+			if err != nil {
+				return err
+			}
+			printBlog(out, blog)
+			return nil
+		},
+	},
+	"delete": {
+		usage: "<id> delete a blog with DeleteBlog",
+		run: func(c pb.BlogServiceClient, args []string, out io.Writer) error {
+			if len(args) != 1 {
+				return fmt.Errorf("want 1 id, got %d", len(args))
+			}
+			return doDelete(context.Background(), c, args[0])
+		},
+	},
+	"list": {
+		usage: "[author] list the blogs, of an author or all of them, with ListBlogs",
+		run: func(c pb.BlogServiceClient, args []string, out io.Writer) error {
+			if len(args) > 1 {
+				return fmt.Errorf("wrong number of arguments: %d", len(args))
+			}
+
+			var author string
+			if len(args) == 1 {
+				author = args[0]
+			}
+
+			return doList(context.Background(), c, author, func(b *pb.Blog) {
+				fmt.Fprintf(out, "%s\t%s\t%s\n", b.Id, b.AuthorId, b.Title)
+			})
+		},
+	},
+}
+
+// updateFields maps the flags of the update command to the fields of the
+// update mask.
+var updateFields = map[string]string{
+	"author":  "author_id",
+	"title":   "title",
+	"content": "content",
+}
+
+func printBlog(out io.Writer, b *pb.Blog) {
+	fmt.Fprintf(out, "id:      %s\nauthor:  %s\ntitle:   %s\nupdated: %s\n\n%s\n",
+		b.Id, b.AuthorId, b.Title, b.UpdateTime.AsTime().Format(time.RFC3339), b.Content)
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupCommand(name string) (command, error) {
+	cmd, ok := commands[name]
+
+	if !ok {
+		return command{}, fmt.Errorf("unknown command %q", name)
+	}
+	return cmd, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+type config struct {
+	addr       string
+	tls        bool
+	caFile     string
+	serverName string
+
+	command string
+	args    []string
+}
+
+// parseConfig reads the client configuration from args. Every flag
+// defaults to the value of its BLOG_* environment variable. The
+// first argument after the flags names the command to run, followed by
+// its arguments.
+func parseConfig(args []string) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("client", flag.ContinueOnError)
+	fs.StringVar(&cfg.addr, "addr", envString("BLOG_ADDR", "localhost:50053"), "server address [BLOG_ADDR]")
+	fs.BoolVar(&cfg.tls, "tls", envBool("BLOG_TLS", true), "connect over TLS [BLOG_TLS]")
+	fs.StringVar(&cfg.caFile, "ca", envString("BLOG_CA", "ssl/ca.crt"), "CA verifying the server certificate [BLOG_CA]")
+	fs.StringVar(&cfg.serverName, "server-name", envString("BLOG_SERVER_NAME", ""), "override the name checked against the server certificate [BLOG_SERVER_NAME]")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: client [flags] command [arguments]\n\nCommands:\n")
+		for _, name := range commandNames() {
+			fmt.Fprintf(fs.Output(), "  %-6s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintf(fs.Output(), "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return config{}, fmt.Errorf("missing command")
+	}
+
+	cfg.command = fs.Arg(0)
+	cfg.args = fs.Args()[1:]
+	return cfg, nil
+}
+
+func envString(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
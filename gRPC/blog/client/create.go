@@ -0,0 +1,11 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+)
+
+func doCreate(ctx context.Context, c pb.BlogServiceClient, blog *pb.Blog) (*pb.Blog, error) {
+	return c.CreateBlog(ctx, &pb.CreateBlogRequest{Blog: blog})
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+)
+
+func doDelete(ctx context.Context, c pb.BlogServiceClient, id string) error {
+	_, err := c.DeleteBlog(ctx, &pb.DeleteBlogRequest{Id: id})
+	return err
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+)
+
+// doList passes the blogs of authorID, or of every author when it is
+// empty, to blog as they arrive.
+func doList(ctx context.Context, c pb.BlogServiceClient, authorID string, blog func(*pb.Blog)) error {
+	stream, err := c.ListBlogs(ctx, &pb.ListBlogsRequest{AuthorId: authorID})
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+		blog(res)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+)
+
+func main() {
+	cfg, err := parseConfig(os.Args[1:])
+
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v\n", err)
+	}
+
+	cmd, err := lookupCommand(cfg.command)
+
+	if err != nil {
+		log.Fatalf("Invalid command: %v\n", err)
+	}
+
+	conn, err := dial(cfg)
+
+	if err != nil {
+		log.Fatalf("Failed to connect: %v\n", err)
+	}
+
+	err = cmd.run(pb.NewBlogServiceClient(conn), cfg.args, os.Stdout)
+	conn.Close()
+
+	if err != nil {
+		log.Printf("%s failed: %v\n", cfg.command, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+)
+
+func doRead(ctx context.Context, c pb.BlogServiceClient, id string) (*pb.Blog, error) {
+	return c.ReadBlog(ctx, &pb.ReadBlogRequest{Id: id})
+}
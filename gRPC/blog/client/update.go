@@ -0,0 +1,17 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// doUpdate sets the fields of blog named by paths, leaving the others as
+// they are on the server.
+func doUpdate(ctx context.Context, c pb.BlogServiceClient, blog *pb.Blog, paths []string) (*pb.Blog, error) {
+	return c.UpdateBlog(ctx, &pb.UpdateBlogRequest{
+		Blog:       blog,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+	})
+}
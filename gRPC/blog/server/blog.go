@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type Server struct {
+	pb.BlogServiceServer
+	store BlogStore
+}
+
+// storeError converts an error of the store into a status: NotFound for
+// unknown ids, Canceled or DeadlineExceeded when the call is over, and
+// Internal otherwise, logging the details.
+func storeError(ctx context.Context, id string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return status.Errorf(codes.NotFound, "blog %q not found", id)
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	log.Printf("Error while accessing the blog store: %v\n", err)
+	return status.Error(codes.Internal, "blog store unavailable")
+}
+
+func (s *Server) CreateBlog(ctx context.Context, in *pb.CreateBlogRequest) (*pb.Blog, error) {
+	if err := validateCreate(in); err != nil {
+		return nil, err
+	}
+
+	b, err := s.store.Create(ctx, in.Blog)
+
+	if err != nil {
+		return nil, storeError(ctx, "", err)
+	}
+	return b, nil
+}
+
+func (s *Server) ReadBlog(ctx context.Context, in *pb.ReadBlogRequest) (*pb.Blog, error) {
+	var v violations
+	checkID(in.Id, "id", &v)
+
+	if err := v.err("ReadBlogRequest"); err != nil {
+		return nil, err
+	}
+
+	b, err := s.store.Read(ctx, in.Id)
+
+	if err != nil {
+		return nil, storeError(ctx, in.Id, err)
+	}
+	return b, nil
+}
+
+func (s *Server) UpdateBlog(ctx context.Context, in *pb.UpdateBlogRequest) (*pb.Blog, error) {
+	paths, err := validateUpdate(in)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.store.Update(ctx, in.Blog, paths)
+
+	if err != nil {
+		return nil, storeError(ctx, in.Blog.Id, err)
+	}
+	return b, nil
+}
+
+func (s *Server) DeleteBlog(ctx context.Context, in *pb.DeleteBlogRequest) (*emptypb.Empty, error) {
+	var v violations
+	checkID(in.Id, "id", &v)
+
+	if err := v.err("DeleteBlogRequest"); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Delete(ctx, in.Id); err != nil {
+		return nil, storeError(ctx, in.Id, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListBlogs(in *pb.ListBlogsRequest, stream pb.BlogService_ListBlogsServer) error {
+	ctx := stream.Context()
+	var sendErr error
+
+	err := s.store.List(ctx, in.AuthorId, func(b *pb.Blog) error {
+		sendErr = stream.Send(b)
+		return sendErr
+	})
+
+	switch {
+	case err == nil:
+		return nil
+	case sendErr != nil:
+		return sendErr
+	}
+	return storeError(ctx, "", err)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/lib/pq" // registers the postgres driver
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"github.com/mxngocqb/Golang/gRPC/certs"
+	"github.com/mxngocqb/Golang/gRPC/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// newServer builds the gRPC server described by cfg, with the blog service
+// backed by store and the health service registered.
+func newServer(cfg config, store BlogStore) (*grpc.Server, error) {
+	logger := slog.Default()
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptor.UnaryLogging(logger), interceptor.UnaryRecovery(logger)),
+		grpc.ChainStreamInterceptor(interceptor.StreamLogging(logger), interceptor.StreamRecovery(logger)),
+	}
+
+	if cfg.tls {
+		keyPair, err := certs.LoadKeyPair(cfg.certFile, cfg.keyFile, 10*time.Second)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading server certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{
+			GetCertificate: keyPair.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		})))
+	}
+
+	s := grpc.NewServer(opts...)
+	pb.RegisterBlogServiceServer(s, &Server{store: store})
+	healthpb.RegisterHealthServer(s, health.NewServer())
+
+	if cfg.reflection {
+		reflection.Register(s)
+	}
+
+	return s, nil
+}
+
+// openStore connects to the Postgres database at url, or returns a memory
+// store when url is empty. The returned function closes the store.
+func openStore(ctx context.Context, url string) (BlogStore, func(), error) {
+	if url == "" {
+		return newMemoryStore(), func() {}, nil
+	}
+
+	db, err := sql.Open("postgres", url)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	store, err := newPostgresStore(ctx, db)
+
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return store, func() { db.Close() }, nil
+}
+
+func main() {
+	cfg, err := parseConfig(os.Args[1:])
+
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v\n", err)
+	}
+
+	store, closeStore, err := openStore(context.Background(), cfg.databaseURL)
+
+	if err != nil {
+		log.Fatalf("Failed to open the blog store: %v\n", err)
+	}
+
+	defer closeStore()
+
+	s, err := newServer(cfg, store)
+
+	if err != nil {
+		log.Fatalf("Failed to set up the server: %v\n", err)
+	}
+
+	lis, err := net.Listen("tcp", cfg.addr)
+
+	if err != nil {
+		log.Fatalf("Failed to listen on: %v\n", err)
+	}
+
+	defer lis.Close()
+
+	log.Printf("Listening on %s (TLS: %v, reflection: %v, Postgres: %v)\n", cfg.addr, cfg.tls, cfg.reflection, cfg.databaseURL != "")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		log.Printf("Shutting down, draining in-flight RPCs for up to %v\n", cfg.drainTimeout)
+		time.AfterFunc(cfg.drainTimeout, s.Stop)
+		s.GracefulStop()
+	}()
+
+	if err = s.Serve(lis); err != nil {
+		log.Fatalf("Failed to serve: %v\n", err)
+	}
+}
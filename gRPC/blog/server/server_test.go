@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// startServer serves the blog service with a memory store over an
+// in-memory listener.
+func startServer(t *testing.T) pb.BlogServiceClient {
+	t.Helper()
+
+	s, err := newServer(config{}, newMemoryStore())
+	require.NoError(t, err)
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewBlogServiceClient(conn)
+}
+
+// violatedFields returns the fields of the BadRequest detail of err.
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code(), st.Message())
+	require.Len(t, st.Details(), 1)
+
+	var fields []string
+	for _, v := range st.Details()[0].(*errdetails.BadRequest).FieldViolations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+func TestBlogCRUD(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	created, err := c.CreateBlog(ctx, &pb.CreateBlogRequest{Blog: &pb.Blog{
+		Id:       "ignored",
+		AuthorId: "ngoc",
+		Title:    "gRPC in Go",
+		Content:  "Streams everywhere",
+	}})
+	require.NoError(t, err)
+	assert.NotEqual(t, "ignored", created.Id)
+	assert.NotNil(t, created.CreateTime)
+	assert.Equal(t, created.CreateTime.AsTime(), created.UpdateTime.AsTime())
+
+	read, err := c.ReadBlog(ctx, &pb.ReadBlogRequest{Id: created.Id})
+	require.NoError(t, err)
+	assert.Equal(t, created.Title, read.Title)
+
+	// Only the fields of the mask change.
+	updated, err := c.UpdateBlog(ctx, &pb.UpdateBlogRequest{
+		Blog:       &pb.Blog{Id: created.Id, Title: "gRPC with Go", Content: "not applied"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "gRPC with Go", updated.Title)
+	assert.Equal(t, "Streams everywhere", updated.Content)
+	assert.Equal(t, "ngoc", updated.AuthorId)
+	assert.False(t, updated.UpdateTime.AsTime().Before(created.UpdateTime.AsTime()))
+
+	// Without a mask, every field does.
+	updated, err = c.UpdateBlog(ctx, &pb.UpdateBlogRequest{
+		Blog: &pb.Blog{Id: created.Id, AuthorId: "clement", Title: "Replaced"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "clement", updated.AuthorId)
+	assert.Empty(t, updated.Content)
+
+	_, err = c.DeleteBlog(ctx, &pb.DeleteBlogRequest{Id: created.Id})
+	require.NoError(t, err)
+
+	_, err = c.ReadBlog(ctx, &pb.ReadBlogRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = c.UpdateBlog(ctx, &pb.UpdateBlogRequest{Blog: &pb.Blog{Id: created.Id, Title: "Gone"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = c.DeleteBlog(ctx, &pb.DeleteBlogRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestBlogValidation(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	_, err := c.CreateBlog(ctx, &pb.CreateBlogRequest{Blog: &pb.Blog{Content: "No title"}})
+	assert.ElementsMatch(t, []string{"blog.author_id", "blog.title"}, violatedFields(t, err))
+
+	_, err = c.CreateBlog(ctx, &pb.CreateBlogRequest{})
+	assert.Equal(t, []string{"blog"}, violatedFields(t, err))
+
+	_, err = c.ReadBlog(ctx, &pb.ReadBlogRequest{})
+	assert.Equal(t, []string{"id"}, violatedFields(t, err))
+
+	_, err = c.DeleteBlog(ctx, &pb.DeleteBlogRequest{})
+	assert.Equal(t, []string{"id"}, violatedFields(t, err))
+
+	_, err = c.UpdateBlog(ctx, &pb.UpdateBlogRequest{
+		Blog:       &pb.Blog{Id: "x", Title: " "},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title", "create_time"}},
+	})
+	assert.ElementsMatch(t, []string{"update_mask", "blog.title"}, violatedFields(t, err))
+
+	// The content may be emptied, the title may not.
+	_, err = c.UpdateBlog(ctx, &pb.UpdateBlogRequest{
+		Blog:       &pb.Blog{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"content"}},
+	})
+	assert.Equal(t, []string{"blog.id"}, violatedFields(t, err))
+}
+
+func listBlogs(t *testing.T, c pb.BlogServiceClient, authorID string) []string {
+	t.Helper()
+
+	stream, err := c.ListBlogs(context.Background(), &pb.ListBlogsRequest{AuthorId: authorID})
+	require.NoError(t, err)
+
+	var titles []string
+	for {
+		b, err := stream.Recv()
+		if err == io.EOF {
+			return titles
+		}
+		require.NoError(t, err)
+		titles = append(titles, b.Title)
+	}
+}
+
+func TestListBlogs(t *testing.T) {
+	c := startServer(t)
+	assert.Empty(t, listBlogs(t, c, ""))
+
+	for _, b := range []*pb.Blog{
+		{AuthorId: "ngoc", Title: "First"},
+		{AuthorId: "clement", Title: "Second"},
+		{AuthorId: "ngoc", Title: "Third"},
+	} {
+		_, err := c.CreateBlog(context.Background(), &pb.CreateBlogRequest{Blog: b})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"First", "Second", "Third"}, listBlogs(t, c, ""))
+	assert.Equal(t, []string{"First", "Third"}, listBlogs(t, c, "ngoc"))
+	assert.Empty(t, listBlogs(t, c, "anh-duc"))
+}
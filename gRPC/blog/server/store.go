@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+)
+
+// ErrNotFound is returned by BlogStore methods for unknown ids.
+var ErrNotFound = errors.New("blog not found")
+
+// BlogStore persists blogs. Requests are validated before they reach it.
+type BlogStore interface {
+	// Create stores b under a new id, with both times set to now.
+	Create(ctx context.Context, b *pb.Blog) (*pb.Blog, error)
+	Read(ctx context.Context, id string) (*pb.Blog, error)
+	// Update copies the fields of b named by paths, a subset of
+	// updatablePaths, to the blog with b.Id and sets its update time.
+	Update(ctx context.Context, b *pb.Blog, paths []string) (*pb.Blog, error)
+	Delete(ctx context.Context, id string) error
+	// List calls fn with the blogs of authorID, or all of them when
+	// empty, oldest first, and stops at the first error fn returns.
+	List(ctx context.Context, authorID string, fn func(*pb.Blog) error) error
+}
+
+// updatablePaths are the field mask paths UpdateBlog accepts, which is
+// every field but the ones set by the server.
+var updatablePaths = []string{"author_id", "title", "content"}
+
+func newID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// now is the time stores stamp blogs with, rounded to the precision of
+// Postgres timestamps so that every store returns the same times.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
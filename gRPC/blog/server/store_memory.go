@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// memoryStore keeps blogs in memory, for tests and demos.
+type memoryStore struct {
+	mu    sync.RWMutex
+	blogs map[string]*pb.Blog
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{blogs: map[string]*pb.Blog{}}
+}
+
+func (m *memoryStore) Create(ctx context.Context, b *pb.Blog) (*pb.Blog, error) {
+	b = proto.Clone(b).(*pb.Blog)
+	b.Id = newID()
+	b.CreateTime = timestamppb.New(now())
+	b.UpdateTime = b.CreateTime
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.blogs[b.Id] = b
+	return proto.Clone(b).(*pb.Blog), nil
+}
+
+func (m *memoryStore) Read(ctx context.Context, id string) (*pb.Blog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	b, ok := m.blogs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(b).(*pb.Blog), nil
+}
+
+func (m *memoryStore) Update(ctx context.Context, b *pb.Blog, paths []string) (*pb.Blog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.blogs[b.Id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	updated := proto.Clone(stored).(*pb.Blog)
+	for _, path := range paths {
+		switch path {
+		case "author_id":
+			updated.AuthorId = b.AuthorId
+		case "title":
+			updated.Title = b.Title
+		case "content":
+			updated.Content = b.Content
+		}
+	}
+	updated.UpdateTime = timestamppb.New(now())
+
+	m.blogs[b.Id] = updated
+	return proto.Clone(updated).(*pb.Blog), nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.blogs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.blogs, id)
+	return nil
+}
+
+func (m *memoryStore) List(ctx context.Context, authorID string, fn func(*pb.Blog) error) error {
+	// Copy the matching blogs, fn may block on a slow client.
+	m.mu.RLock()
+	var blogs []*pb.Blog
+	for _, b := range m.blogs {
+		if authorID == "" || b.AuthorId == authorID {
+			blogs = append(blogs, proto.Clone(b).(*pb.Blog))
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(blogs, func(i, j int) bool {
+		ti, tj := blogs[i].CreateTime.AsTime(), blogs[j].CreateTime.AsTime()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return blogs[i].Id < blogs[j].Id
+	})
+
+	for _, b := range blogs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const blogSchema = `
+CREATE TABLE IF NOT EXISTS blogs (
+	id          TEXT PRIMARY KEY,
+	author_id   TEXT NOT NULL,
+	title       TEXT NOT NULL,
+	content     TEXT NOT NULL,
+	create_time TIMESTAMPTZ NOT NULL,
+	update_time TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS blogs_author_id ON blogs (author_id, create_time, id);
+`
+
+const blogColumns = "id, author_id, title, content, create_time, update_time"
+
+// postgresStore keeps blogs in the blogs table of a Postgres database.
+type postgresStore struct {
+	db *sql.DB
+}
+
+// newPostgresStore creates the blogs table in db if it does not exist yet.
+func newPostgresStore(ctx context.Context, db *sql.DB) (*postgresStore, error) {
+	if _, err := db.ExecContext(ctx, blogSchema); err != nil {
+		return nil, fmt.Errorf("creating the blogs table: %w", err)
+	}
+	return &postgresStore{db: db}, nil
+}
+
+// scanner is a *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBlog(row scanner) (*pb.Blog, error) {
+	var b pb.Blog
+	var created, updated time.Time
+
+	err := row.Scan(&b.Id, &b.AuthorId, &b.Title, &b.Content, &created, &updated)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	b.CreateTime, b.UpdateTime = timestamppb.New(created), timestamppb.New(updated)
+	return &b, nil
+}
+
+func (p *postgresStore) Create(ctx context.Context, b *pb.Blog) (*pb.Blog, error) {
+	t := now()
+	row := p.db.QueryRowContext(ctx,
+		"INSERT INTO blogs ("+blogColumns+") VALUES ($1, $2, $3, $4, $5, $5) RETURNING "+blogColumns,
+		newID(), b.AuthorId, b.Title, b.Content, t)
+	return scanBlog(row)
+}
+
+func (p *postgresStore) Read(ctx context.Context, id string) (*pb.Blog, error) {
+	row := p.db.QueryRowContext(ctx, "SELECT "+blogColumns+" FROM blogs WHERE id = $1", id)
+	return scanBlog(row)
+}
+
+func (p *postgresStore) Update(ctx context.Context, b *pb.Blog, paths []string) (*pb.Blog, error) {
+	args := []any{b.Id, now()}
+	set := []string{"update_time = $2"}
+
+	// paths only hold updatablePaths, which are column names.
+	for _, path := range paths {
+		var v string
+		switch path {
+		case "author_id":
+			v = b.AuthorId
+		case "title":
+			v = b.Title
+		case "content":
+			v = b.Content
+		default:
+			return nil, fmt.Errorf("cannot update %q", path)
+		}
+
+		args = append(args, v)
+		set = append(set, fmt.Sprintf("%s = $%d", path, len(args)))
+	}
+
+	row := p.db.QueryRowContext(ctx,
+		"UPDATE blogs SET "+strings.Join(set, ", ")+" WHERE id = $1 RETURNING "+blogColumns, args...)
+	return scanBlog(row)
+}
+
+func (p *postgresStore) Delete(ctx context.Context, id string) error {
+	res, err := p.db.ExecContext(ctx, "DELETE FROM blogs WHERE id = $1", id)
+
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *postgresStore) List(ctx context.Context, authorID string, fn func(*pb.Blog) error) error {
+	rows, err := p.db.QueryContext(ctx,
+		"SELECT "+blogColumns+" FROM blogs WHERE $1 = '' OR author_id = $1 ORDER BY create_time, id", authorID)
+
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		b, err := scanBlog(rows)
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
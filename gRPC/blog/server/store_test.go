@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore checks the behavior every BlogStore shares.
+func testStore(t *testing.T, store BlogStore) {
+	ctx := context.Background()
+
+	a, err := store.Create(ctx, &pb.Blog{AuthorId: "store-test-a", Title: "A", Content: "a"})
+	require.NoError(t, err)
+	b, err := store.Create(ctx, &pb.Blog{AuthorId: "store-test-b", Title: "B"})
+	require.NoError(t, err)
+	assert.NotEqual(t, a.Id, b.Id)
+
+	read, err := store.Read(ctx, a.Id)
+	require.NoError(t, err)
+	assert.Equal(t, a.Title, read.Title)
+	assert.True(t, a.CreateTime.AsTime().Equal(read.CreateTime.AsTime()))
+
+	updated, err := store.Update(ctx, &pb.Blog{Id: a.Id, Title: "A2", Content: "ignored"}, []string{"title"})
+	require.NoError(t, err)
+	assert.Equal(t, "A2", updated.Title)
+	assert.Equal(t, "a", updated.Content)
+
+	var titles []string
+	err = store.List(ctx, "store-test-a", func(b *pb.Blog) error {
+		titles = append(titles, b.Title)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A2"}, titles)
+
+	// List stops at the first error of fn.
+	stop := errors.New("stop")
+	calls := 0
+	err = store.List(ctx, "", func(*pb.Blog) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, store.Delete(ctx, a.Id))
+	require.NoError(t, store.Delete(ctx, b.Id))
+
+	_, err = store.Read(ctx, a.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Update(ctx, &pb.Blog{Id: a.Id}, []string{"title"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, a.Id), ErrNotFound)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, newMemoryStore())
+}
+
+// TestPostgresStore runs against the database at BLOG_TEST_DATABASE_URL,
+// in which it creates the blogs table if needed.
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("BLOG_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("BLOG_TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store, err := newPostgresStore(context.Background(), db)
+	require.NoError(t, err)
+	testStore(t, store)
+}
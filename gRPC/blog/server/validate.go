@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	pb "github.com/mxngocqb/Golang/gRPC/blog/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// maxTitleLength is the longest title accepted, in characters.
+const maxTitleLength = 200
+
+// violations are the invalid fields of a request.
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+// err returns an InvalidArgument status for the request named message,
+// listing every violation as a BadRequest field violation, or nil when
+// there is none.
+func (v violations) err(message string) error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	st, err := status.New(codes.InvalidArgument, "invalid "+message).
+		WithDetails(&errdetails.BadRequest{FieldViolations: v})
+
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid "+message)
+	}
+	return st.Err()
+}
+
+// checkBlogField checks the field of b named path, reporting violations
+// under prefix.
+func checkBlogField(b *pb.Blog, path, prefix string, v *violations) {
+	switch path {
+	case "author_id":
+		if strings.TrimSpace(b.AuthorId) == "" {
+			v.add(prefix+"author_id", "must not be empty")
+		}
+	case "title":
+		if strings.TrimSpace(b.Title) == "" {
+			v.add(prefix+"title", "must not be empty")
+		}
+		if utf8.RuneCountInString(b.Title) > maxTitleLength {
+			v.add(prefix+"title", fmt.Sprintf("must be at most %d characters", maxTitleLength))
+		}
+	}
+}
+
+func checkID(id, field string, v *violations) {
+	if id == "" {
+		v.add(field, "must not be empty")
+	}
+}
+
+func validateCreate(in *pb.CreateBlogRequest) error {
+	var v violations
+
+	if in.Blog == nil {
+		v.add("blog", "is required")
+	} else {
+		for _, path := range updatablePaths {
+			checkBlogField(in.Blog, path, "blog.", &v)
+		}
+	}
+	return v.err("CreateBlogRequest")
+}
+
+// validateUpdate checks in and returns the paths to update.
+func validateUpdate(in *pb.UpdateBlogRequest) ([]string, error) {
+	var v violations
+
+	if in.Blog == nil {
+		v.add("blog", "is required")
+		return nil, v.err("UpdateBlogRequest")
+	}
+	checkID(in.Blog.Id, "blog.id", &v)
+
+	paths := updatablePaths
+	if len(in.UpdateMask.GetPaths()) > 0 {
+		mask := proto.Clone(in.UpdateMask).(*fieldmaskpb.FieldMask)
+		mask.Normalize()
+		paths = mask.Paths
+
+		for _, path := range paths {
+			if !updatable(path) {
+				v.add("update_mask", fmt.Sprintf("cannot update %q, only %s", path, strings.Join(updatablePaths, ", ")))
+			}
+		}
+	}
+
+	for _, path := range paths {
+		checkBlogField(in.Blog, path, "blog.", &v)
+	}
+
+	if err := v.err("UpdateBlogRequest"); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func updatable(path string) bool {
+	for _, p := range updatablePaths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+)
+
+func doAverage(ctx context.Context, c pb.CalculatorServiceClient, numbers []int64) (float64, error) {
+	stream, err := c.Average(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range numbers {
+		if err := stream.Send(&pb.AverageRequest{Number: n}); err != nil {
+			// The status of the call tells why it broke.
+			break
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+
+	if err != nil {
+		return 0, err
+	}
+	return res.Result, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"testing"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeCalculator answers with canned results, and records the numbers the
+// client streams.
+type fakeCalculator struct {
+	pb.UnimplementedCalculatorServiceServer
+	received []int64
+}
+
+func (f *fakeCalculator) Sum(ctx context.Context, in *pb.SumRequest) (*pb.SumResponse, error) {
+	return &pb.SumResponse{Result: in.FirstNumber + in.SecondNumber}, nil
+}
+
+func (f *fakeCalculator) PrimeNumberDecomposition(in *pb.PrimeRequest, stream pb.CalculatorService_PrimeNumberDecompositionServer) error {
+	for _, p := range []uint64{2, 2, 3} {
+		if err := stream.Send(&pb.PrimeResponse{Result: p}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeCalculator) Average(stream pb.CalculatorService_AverageServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.AverageResponse{Result: 1.5})
+		}
+		if err != nil {
+			return err
+		}
+		f.received = append(f.received, req.Number)
+	}
+}
+
+func (f *fakeCalculator) Max(stream pb.CalculatorService_MaxServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.MaxResponse{Result: req.Number}); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *fakeCalculator) SquareRoot(ctx context.Context, in *pb.SqrtRequest) (*pb.SqrtResponse, error) {
+	if in.Number < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative")
+	}
+	return &pb.SqrtResponse{Result: math.Sqrt(in.Number)}, nil
+}
+
+func dialFake(t *testing.T, f *fakeCalculator) pb.CalculatorServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterCalculatorServiceServer(s, f)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewCalculatorServiceClient(conn)
+}
+
+func TestCommands(t *testing.T) {
+	f := &fakeCalculator{}
+	c := dialFake(t, f)
+
+	for _, tc := range []struct {
+		line string
+		want string
+	}{
+		{"sum 3 10", "13"},
+		{"primes 12", "2\n2\n3"},
+		{"average 1 2", "1.5"},
+		{"max 1 5 3", "1\n5\n3"},
+		{"sqrt 25", "5"},
+	} {
+		args := strings.Fields(tc.line)
+		cmd, err := lookupCommand(args[0])
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, cmd.run(c, args[1:], &out), tc.line)
+		assert.Equal(t, tc.want, strings.TrimSpace(out.String()), tc.line)
+	}
+	assert.Equal(t, []int64{1, 2}, f.received)
+}
+
+func TestCommandErrors(t *testing.T) {
+	c := dialFake(t, &fakeCalculator{})
+
+	for _, line := range []string{"sum 1", "sum 1 x", "primes", "primes -3", "average", "max", "sqrt"} {
+		args := strings.Fields(line)
+		cmd, err := lookupCommand(args[0])
+		require.NoError(t, err)
+		assert.Error(t, cmd.run(c, args[1:], io.Discard), line)
+	}
+
+	err := commands["sqrt"].run(c, []string{"-4"}, io.Discard)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = lookupCommand("divide")
+	assert.Error(t, err)
+}
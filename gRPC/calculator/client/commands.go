@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+)
+
+type command struct {
+	usage string
+	run   func(c pb.CalculatorServiceClient, args []string, out io.Writer) error
+}
+
+var commands = map[string]command{
+	"sum": {
+		usage: "<a> <b> add two integers with Sum",
+		run: func(c pb.CalculatorServiceClient, args []string, out io.Writer) error {
+			numbers, err := parseInts(args, 2, 2)
+
+			if err != nil {
+				return err
+			}
+
+			sum, err := doSum(context.Background(), c, numbers[0], numbers[1])
+
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, sum)
+			return nil
+		},
+	},
+	"primes": {
+		usage: "<n> decompose n into prime factors with PrimeNumberDecomposition",
+		run: func(c pb.CalculatorServiceClient, args []string, out io.Writer) error {
+			if len(args) != 1 {
+				return fmt.Errorf("want 1 number, got %d", len(args))
+			}
+
+			n, err := strconv.ParseUint(args[0], 10, 64)
+
+			if err != nil {
+				return err
+			}
+
+			return doPrimes(context.Background(), c, n, func(factor uint64) {
+				fmt.Fprintln(out, factor)
+			})
+		},
+	},
+	"average": {
+		usage: "<n>... average integers with Average",
+		run: func(c pb.CalculatorServiceClient, args []string, out io.Writer) error {
+			numbers, err := parseInts(args, 1, -1)
+
+			if err != nil {
+				return err
+			}
+
+			avg, err := doAverage(context.Background(), c, numbers)
+
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, avg)
+			return nil
+		},
+	},
+	"max": {
+		usage: "<n>... print the running maximum of integers with Max",
+		run: func(c pb.CalculatorServiceClient, args []string, out io.Writer) error {
+			numbers, err := parseInts(args, 1, -1)
+
+			if err != nil {
+				return err
+			}
+
+			return doMax(context.Background(), c, numbers, func(m int64) {
+				fmt.Fprintln(out, m)
+			})
+		},
+	},
+	"sqrt": {
+		usage: "<x> take the square root of x with SquareRoot",
+		run: func(c pb.CalculatorServiceClient, args []string, out io.Writer) error {
+			if len(args) != 1 {
+				return fmt.Errorf("want 1 number, got %d", len(args))
+			}
+
+			x, err := strconv.ParseFloat(args[0], 64)
+
+			if err != nil {
+				return err
+			}
+
+			root, err := doSqrt(context.Background(), c, x)
+
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, root)
+			return nil
+		},
+	},
+}
+
+// parseInts parses args as integers, expecting at least least of them and
+// at most most, or any number when most is negative.
+func parseInts(args []string, least, most int) ([]int64, error) {
+	if len(args) < least || (most >= 0 && len(args) > most) {
+		return nil, fmt.Errorf("wrong number of arguments: %d", len(args))
+	}
+
+	numbers := make([]int64, len(args))
+	for i, arg := range args {
+		n, err := strconv.ParseInt(arg, 10, 64)
+
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupCommand(name string) (command, error) {
+	cmd, ok := commands[name]
+
+	if !ok {
+		return command{}, fmt.Errorf("unknown command %q", name)
+	}
+	return cmd, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+type config struct {
+	addr       string
+	tls        bool
+	caFile     string
+	serverName string
+
+	command string
+	args    []string
+}
+
+// parseConfig reads the client configuration from args. Every flag
+// defaults to the value of its CALCULATOR_* environment variable. The
+// first argument after the flags names the command to run, followed by
+// its numbers.
+func parseConfig(args []string) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("client", flag.ContinueOnError)
+	fs.StringVar(&cfg.addr, "addr", envString("CALCULATOR_ADDR", "localhost:50052"), "server address [CALCULATOR_ADDR]")
+	fs.BoolVar(&cfg.tls, "tls", envBool("CALCULATOR_TLS", true), "connect over TLS [CALCULATOR_TLS]")
+	fs.StringVar(&cfg.caFile, "ca", envString("CALCULATOR_CA", "ssl/ca.crt"), "CA verifying the server certificate [CALCULATOR_CA]")
+	fs.StringVar(&cfg.serverName, "server-name", envString("CALCULATOR_SERVER_NAME", ""), "override the name checked against the server certificate [CALCULATOR_SERVER_NAME]")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: client [flags] command [numbers]\n\nCommands:\n")
+		for _, name := range commandNames() {
+			fmt.Fprintf(fs.Output(), "  %-8s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintf(fs.Output(), "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return config{}, fmt.Errorf("missing command")
+	}
+
+	cfg.command = fs.Arg(0)
+	cfg.args = fs.Args()[1:]
+	return cfg, nil
+}
+
+func envString(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func dial(cfg config) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+
+	if cfg.tls {
+		pem, err := os.ReadFile(cfg.caFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading CA trust certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", cfg.caFile)
+		}
+
+		creds = credentials.NewTLS(&tls.Config{
+			RootCAs:    pool,
+			ServerName: cfg.serverName,
+			MinVersion: tls.VersionTLS12,
+		})
+	}
+
+	return grpc.Dial(cfg.addr, grpc.WithTransportCredentials(creds))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+)
+
+// doMax sends numbers to Max while passing each new maximum to update as
+// it arrives.
+func doMax(ctx context.Context, c pb.CalculatorServiceClient, numbers []int64, update func(int64)) error {
+	stream, err := c.Max(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for _, n := range numbers {
+			if err := stream.Send(&pb.MaxRequest{Number: n}); err != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+		update(res.Result)
+	}
+}
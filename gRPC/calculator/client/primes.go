@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+)
+
+// doPrimes passes the prime factors of n to factor as they arrive.
+func doPrimes(ctx context.Context, c pb.CalculatorServiceClient, n uint64, factor func(uint64)) error {
+	stream, err := c.PrimeNumberDecomposition(ctx, &pb.PrimeRequest{Number: n})
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+		factor(res.Result)
+	}
+}
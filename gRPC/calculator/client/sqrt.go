@@ -0,0 +1,16 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+)
+
+func doSqrt(ctx context.Context, c pb.CalculatorServiceClient, x float64) (float64, error) {
+	res, err := c.SquareRoot(ctx, &pb.SqrtRequest{Number: x})
+
+	if err != nil {
+		return 0, err
+	}
+	return res.Result, nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+)
+
+func doSum(ctx context.Context, c pb.CalculatorServiceClient, a, b int64) (int64, error) {
+	res, err := c.Sum(ctx, &pb.SumRequest{
+		FirstNumber:  a,
+		SecondNumber: b,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return res.Result, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"io"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) Average(stream pb.CalculatorService_AverageServer) error {
+	var sum float64
+	var count int
+
+	for {
+		req, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		sum += float64(req.Number)
+		count++
+	}
+
+	if count == 0 {
+		return status.Error(codes.InvalidArgument, "no number to average")
+	}
+
+	return stream.SendAndClose(&pb.AverageResponse{
+		Result: sum / float64(count),
+	})
+}
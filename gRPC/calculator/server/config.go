@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"time"
+)
+
+type config struct {
+	addr     string
+	tls      bool
+	certFile string
+	keyFile  string
+
+	reflection   bool
+	drainTimeout time.Duration
+}
+
+// parseConfig reads the server configuration from args. Every flag
+// defaults to the value of its CALCULATOR_* environment variable, so flags
+// override the environment.
+func parseConfig(args []string) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.StringVar(&cfg.addr, "addr", envString("CALCULATOR_ADDR", "localhost:50052"), "address to listen on [CALCULATOR_ADDR]")
+	fs.BoolVar(&cfg.tls, "tls", envBool("CALCULATOR_TLS", true), "serve over TLS [CALCULATOR_TLS]")
+	fs.StringVar(&cfg.certFile, "cert", envString("CALCULATOR_CERT", "ssl/server.crt"), "server certificate, reloaded when it changes [CALCULATOR_CERT]")
+	fs.StringVar(&cfg.keyFile, "key", envString("CALCULATOR_KEY", "ssl/server.pem"), "server private key [CALCULATOR_KEY]")
+	fs.BoolVar(&cfg.reflection, "reflection", envBool("CALCULATOR_REFLECTION", false), "enable server reflection [CALCULATOR_REFLECTION]")
+	fs.DurationVar(&cfg.drainTimeout, "drain-timeout", envDuration("CALCULATOR_DRAIN_TIMEOUT", 15*time.Second), "time given to in-flight RPCs on shutdown [CALCULATOR_DRAIN_TIMEOUT]")
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+	return cfg, nil
+}
+
+func envString(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+	"github.com/mxngocqb/Golang/gRPC/certs"
+	"github.com/mxngocqb/Golang/gRPC/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	pb.CalculatorServiceServer
+}
+
+// newServer builds the gRPC server described by cfg, with the calculator
+// and health services registered.
+func newServer(cfg config) (*grpc.Server, error) {
+	logger := slog.Default()
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptor.UnaryLogging(logger), interceptor.UnaryRecovery(logger)),
+		grpc.ChainStreamInterceptor(interceptor.StreamLogging(logger), interceptor.StreamRecovery(logger)),
+	}
+
+	if cfg.tls {
+		keyPair, err := certs.LoadKeyPair(cfg.certFile, cfg.keyFile, 10*time.Second)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading server certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{
+			GetCertificate: keyPair.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		})))
+	}
+
+	s := grpc.NewServer(opts...)
+	pb.RegisterCalculatorServiceServer(s, &Server{})
+	healthpb.RegisterHealthServer(s, health.NewServer())
+
+	if cfg.reflection {
+		reflection.Register(s)
+	}
+
+	return s, nil
+}
+
+func main() {
+	cfg, err := parseConfig(os.Args[1:])
+
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v\n", err)
+	}
+
+	s, err := newServer(cfg)
+
+	if err != nil {
+		log.Fatalf("Failed to set up the server: %v\n", err)
+	}
+
+	lis, err := net.Listen("tcp", cfg.addr)
+
+	if err != nil {
+		log.Fatalf("Failed to listen on: %v\n", err)
+	}
+
+	defer lis.Close()
+
+	log.Printf("Listening on %s (TLS: %v, reflection: %v)\n", cfg.addr, cfg.tls, cfg.reflection)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		log.Printf("Shutting down, draining in-flight RPCs for up to %v\n", cfg.drainTimeout)
+		time.AfterFunc(cfg.drainTimeout, s.Stop)
+		s.GracefulStop()
+	}()
+
+	if err = s.Serve(lis); err != nil {
+		log.Fatalf("Failed to serve: %v\n", err)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+)
+
+func (s *Server) Max(stream pb.CalculatorService_MaxServer) error {
+	var max int64
+	first := true
+
+	for {
+		req, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !first && req.Number <= max {
+			continue
+		}
+		max, first = req.Number, false
+
+		if err := stream.Send(&pb.MaxResponse{Result: max}); err != nil {
+			return err
+		}
+	}
+}
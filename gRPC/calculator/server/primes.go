@@ -0,0 +1,41 @@
+package main
+
+import (
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) PrimeNumberDecomposition(in *pb.PrimeRequest, stream pb.CalculatorService_PrimeNumberDecompositionServer) error {
+	n := in.Number
+
+	if n == 0 {
+		return status.Error(codes.InvalidArgument, "number must be positive")
+	}
+
+	ctx := stream.Context()
+	divisor := uint64(2)
+
+	// Trial division takes up to 2^32 steps for large primes, give up
+	// as soon as the client does.
+	for steps := 0; divisor <= n/divisor; steps++ {
+		if steps%4096 == 0 && ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		if n%divisor != 0 {
+			divisor++
+			continue
+		}
+
+		if err := stream.Send(&pb.PrimeResponse{Result: divisor}); err != nil {
+			return err
+		}
+		n /= divisor
+	}
+
+	if n > 1 {
+		return stream.Send(&pb.PrimeResponse{Result: n})
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startServer serves the calculator over an in-memory listener.
+func startServer(t *testing.T) pb.CalculatorServiceClient {
+	t.Helper()
+
+	s, err := newServer(config{})
+	require.NoError(t, err)
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewCalculatorServiceClient(conn)
+}
+
+func TestSum(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	res, err := c.Sum(ctx, &pb.SumRequest{FirstNumber: 3, SecondNumber: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(13), res.Result)
+
+	res, err = c.Sum(ctx, &pb.SumRequest{FirstNumber: math.MinInt64, SecondNumber: math.MaxInt64})
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), res.Result)
+
+	_, err = c.Sum(ctx, &pb.SumRequest{FirstNumber: math.MaxInt64, SecondNumber: 1})
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+	_, err = c.Sum(ctx, &pb.SumRequest{FirstNumber: math.MinInt64, SecondNumber: -1})
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+}
+
+func decompose(t *testing.T, c pb.CalculatorServiceClient, ctx context.Context, n uint64) ([]uint64, error) {
+	t.Helper()
+
+	stream, err := c.PrimeNumberDecomposition(ctx, &pb.PrimeRequest{Number: n})
+	require.NoError(t, err)
+
+	var factors []uint64
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return factors, nil
+		}
+		if err != nil {
+			return factors, err
+		}
+		factors = append(factors, res.Result)
+	}
+}
+
+func TestPrimeNumberDecomposition(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	for n, want := range map[uint64][]uint64{
+		1:                  nil,
+		2:                  {2},
+		97:                 {97},
+		120:                {2, 2, 2, 3, 5},
+		2 * 4294967291:     {2, 4294967291},
+		65521 * 4294967291: {65521, 4294967291},
+		math.MaxUint64 - 1: {2, 7, 7, 73, 127, 337, 92737, 649657},
+	} {
+		factors, err := decompose(t, c, ctx, n)
+		require.NoError(t, err)
+		assert.Equal(t, want, factors, "decomposing %d", n)
+	}
+
+	_, err := decompose(t, c, ctx, 0)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPrimeNumberDecompositionStopsWithTheClient(t *testing.T) {
+	c := startServer(t)
+
+	// The largest 64-bit prime would take billions of divisions.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := decompose(t, c, ctx, 18446744073709551557)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestAverage(t *testing.T) {
+	c := startServer(t)
+
+	stream, err := c.Average(context.Background())
+	require.NoError(t, err)
+	for _, n := range []int64{1, 2, 3, 4} {
+		require.NoError(t, stream.Send(&pb.AverageRequest{Number: n}))
+	}
+	res, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, res.Result)
+
+	stream, err = c.Average(context.Background())
+	require.NoError(t, err)
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestMax(t *testing.T) {
+	c := startServer(t)
+
+	stream, err := c.Max(context.Background())
+	require.NoError(t, err)
+
+	go func() {
+		for _, n := range []int64{-7, 1, 5, 3, 6, 2, 20} {
+			stream.Send(&pb.MaxRequest{Number: n})
+		}
+		stream.CloseSend()
+	}()
+
+	var maxes []int64
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		maxes = append(maxes, res.Result)
+	}
+	assert.Equal(t, []int64{-7, 1, 5, 6, 20}, maxes)
+}
+
+func TestSquareRoot(t *testing.T) {
+	c := startServer(t)
+	ctx := context.Background()
+
+	res, err := c.SquareRoot(ctx, &pb.SqrtRequest{Number: 25})
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, res.Result)
+
+	for _, n := range []float64{-4, math.NaN()} {
+		_, err = c.SquareRoot(ctx, &pb.SqrtRequest{Number: n})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "square root of %v", n)
+	}
+}
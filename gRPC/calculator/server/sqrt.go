@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"math"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) SquareRoot(ctx context.Context, in *pb.SqrtRequest) (*pb.SqrtResponse, error) {
+	number := in.Number
+
+	if number < 0 || math.IsNaN(number) {
+		return nil, status.Errorf(codes.InvalidArgument, "%v has no real square root", number)
+	}
+
+	return &pb.SqrtResponse{
+		Result: math.Sqrt(number),
+	}, nil
+}
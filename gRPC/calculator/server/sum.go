@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/mxngocqb/Golang/gRPC/calculator/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) Sum(ctx context.Context, in *pb.SumRequest) (*pb.SumResponse, error) {
+	a, b := in.FirstNumber, in.SecondNumber
+	sum := a + b
+
+	// The sum of two numbers of the same sign has that sign too, unless
+	// it overflowed.
+	if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		return nil, status.Errorf(codes.OutOfRange, "%d + %d overflows a 64-bit integer", a, b)
+	}
+
+	return &pb.SumResponse{
+		Result: sum,
+	}, nil
+}
@@ -0,0 +1,133 @@
+// Command certgen writes the TLS material the greet server and client use
+// by default: a CA, a server certificate and a client certificate for
+// mTLS. An existing CA in the output directory is reused, so that servers
+// can be given new certificates without redistributing the CA.
+//
+//	go run ./certgen -out ssl -hosts localhost,127.0.0.1
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/certs"
+)
+
+type config struct {
+	out   string
+	newCA bool
+
+	caName     string
+	caValidity time.Duration
+
+	serverName string
+	hosts      []string
+	clientName string
+	validity   time.Duration
+}
+
+func parseConfig(args []string) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("certgen", flag.ContinueOnError)
+	fs.StringVar(&cfg.out, "out", "ssl", "output directory")
+	fs.BoolVar(&cfg.newCA, "new-ca", false, "create a new CA even if one exists in -out")
+	fs.StringVar(&cfg.caName, "ca-name", "ca", "common name of the CA")
+	fs.DurationVar(&cfg.caValidity, "ca-validity", 5*365*24*time.Hour, "lifetime of a new CA")
+	fs.StringVar(&cfg.serverName, "server-name", "localhost", "common name of the server certificate")
+	hosts := fs.String("hosts", "localhost,127.0.0.1,::1", "comma separated DNS names and IP addresses of the server certificate")
+	fs.StringVar(&cfg.clientName, "client-name", "greet-client", "common name of the client certificate, empty to skip it")
+	fs.DurationVar(&cfg.validity, "validity", 90*24*time.Hour, "lifetime of the server and client certificates")
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	for _, h := range strings.Split(*hosts, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			cfg.hosts = append(cfg.hosts, h)
+		}
+	}
+
+	if cfg.caValidity <= 0 || cfg.validity <= 0 {
+		return config{}, errors.New("-ca-validity and -validity must be positive")
+	}
+	return cfg, nil
+}
+
+// run writes ca.crt and ca.key, server.crt and server.pem, and client.crt
+// and client.pem into cfg.out.
+func run(cfg config) error {
+	if err := os.MkdirAll(cfg.out, 0o755); err != nil {
+		return err
+	}
+
+	path := func(name string) string { return filepath.Join(cfg.out, name) }
+
+	ca, err := certs.LoadAuthority(path("ca.crt"), path("ca.key"))
+
+	switch {
+	case err == nil && !cfg.newCA:
+		log.Printf("Using the CA in %s, valid until %v\n", path("ca.crt"), ca.Cert.NotAfter.Format(time.DateOnly))
+	case err == nil || errors.Is(err, os.ErrNotExist):
+		ca, err = certs.NewAuthority(cfg.caName, cfg.caValidity)
+
+		if err != nil {
+			return err
+		}
+
+		if err := ca.WriteFiles(path("ca.crt"), path("ca.key")); err != nil {
+			return err
+		}
+		log.Printf("Wrote a new CA to %s\n", path("ca.crt"))
+	default:
+		return fmt.Errorf("loading the CA: %w", err)
+	}
+
+	err = ca.IssueFiles(certs.Request{
+		CommonName: cfg.serverName,
+		Hosts:      cfg.hosts,
+		Validity:   cfg.validity,
+		Usage:      x509.ExtKeyUsageServerAuth,
+	}, path("server.crt"), path("server.pem"))
+
+	if err != nil {
+		return err
+	}
+	log.Printf("Wrote the server certificate for %s to %s\n", strings.Join(cfg.hosts, ", "), path("server.crt"))
+
+	if cfg.clientName == "" {
+		return nil
+	}
+
+	err = ca.IssueFiles(certs.Request{
+		CommonName: cfg.clientName,
+		Validity:   cfg.validity,
+		Usage:      x509.ExtKeyUsageClientAuth,
+	}, path("client.crt"), path("client.pem"))
+
+	if err != nil {
+		return err
+	}
+	log.Printf("Wrote the client certificate of %s to %s\n", cfg.clientName, path("client.crt"))
+	return nil
+}
+
+func main() {
+	cfg, err := parseConfig(os.Args[1:])
+
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v\n", err)
+	}
+
+	if err := run(cfg); err != nil {
+		log.Fatalf("Failed to generate the certificates: %v\n", err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReusesTheCA(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := parseConfig([]string{"-out", dir, "-hosts", "greet.example.com, 127.0.0.1"})
+	require.NoError(t, err)
+
+	require.NoError(t, run(cfg))
+	ca, err := os.ReadFile(filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca))
+	verify := func(name string, usage x509.ExtKeyUsage) {
+		t.Helper()
+
+		pair, err := tls.LoadX509KeyPair(filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".pem"))
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		require.NoError(t, err)
+		_, err = cert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{usage}})
+		assert.NoError(t, err)
+	}
+	verify("server", x509.ExtKeyUsageServerAuth)
+	verify("client", x509.ExtKeyUsageClientAuth)
+
+	// A second run renews the certificates with the same CA.
+	require.NoError(t, run(cfg))
+	again, err := os.ReadFile(filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+	assert.Equal(t, ca, again)
+	verify("server", x509.ExtKeyUsageServerAuth)
+
+	cfg.newCA = true
+	require.NoError(t, run(cfg))
+	again, err = os.ReadFile(filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+	assert.NotEqual(t, ca, again)
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := parseConfig([]string{"-hosts", "a, ,b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, cfg.hosts)
+
+	_, err = parseConfig([]string{"-validity", "0s"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,270 @@
+// Package certs creates the TLS material of this module: a CA, and the
+// server and client certificates it signs. Keys are ECDSA P-256, stored
+// as PKCS #8 PEM files readable by their owner only.
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// backdate is how far in the past certificates start being valid, to
+// allow for clock skew between hosts.
+const backdate = 5 * time.Minute
+
+// Authority signs certificates.
+type Authority struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// NewAuthority creates a self-signed CA named cn, valid for validity.
+func NewAuthority(cn string, validity time.Duration) (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerial()
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              now.Add(validity),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+
+	if err != nil {
+		return nil, fmt.Errorf("creating the CA certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		return nil, err
+	}
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// LoadAuthority reads a CA written by WriteFiles.
+func LoadAuthority(certFile, keyFile string) (*Authority, error) {
+	certPEM, err := os.ReadFile(certFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := parseCertificate(certPEM)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", certFile, err)
+	}
+
+	if !cert.IsCA {
+		return nil, fmt.Errorf("%s is not a CA certificate", certFile)
+	}
+
+	key, err := parseKey(keyPEM)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyFile, err)
+	}
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// CertPEM returns the certificate of the CA, which clients and servers
+// verify their peers with.
+func (a *Authority) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.Cert.Raw})
+}
+
+// WriteFiles writes the certificate and the key of the CA.
+func (a *Authority) WriteFiles(certFile, keyFile string) error {
+	keyPEM, err := encodeKey(a.Key)
+
+	if err != nil {
+		return err
+	}
+	return writePair(certFile, a.CertPEM(), keyFile, keyPEM)
+}
+
+// Request describes a certificate to issue.
+type Request struct {
+	CommonName string
+	// Hosts are the subject alternative names, DNS names or IP addresses.
+	Hosts    []string
+	Validity time.Duration
+	// Usage is x509.ExtKeyUsageServerAuth or x509.ExtKeyUsageClientAuth.
+	Usage x509.ExtKeyUsage
+}
+
+// Issue creates a key and a certificate for it signed by the CA, both
+// PEM encoded. The certificate does not outlive the CA.
+func (a *Authority) Issue(req Request) (certPEM, keyPEM []byte, err error) {
+	if req.CommonName == "" {
+		return nil, nil, errors.New("a common name is required")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := newSerial()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: req.CommonName},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(req.Validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{req.Usage},
+	}
+
+	if tmpl.NotAfter.After(a.Cert.NotAfter) {
+		tmpl.NotAfter = a.Cert.NotAfter
+	}
+
+	for _, h := range req.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.Cert, &key.PublicKey, a.Key)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating the certificate of %s: %w", req.CommonName, err)
+	}
+
+	keyPEM, err = encodeKey(key)
+
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// IssueFiles issues a certificate as Issue does and writes it, and its
+// key, to certFile and keyFile.
+func (a *Authority) IssueFiles(req Request, certFile, keyFile string) error {
+	certPEM, keyPEM, err := a.Issue(req)
+
+	if err != nil {
+		return err
+	}
+	return writePair(certFile, certPEM, keyFile, keyPEM)
+}
+
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encodeKey(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func parseCertificate(b []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseKey(b []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("no PKCS #8 PEM private key found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// writePair writes the key before the certificate, so that a server
+// reloading on certificate changes finds the matching key.
+func writePair(certFile string, certPEM []byte, keyFile string, keyPEM []byte) error {
+	if err := writeFile(keyFile, keyPEM, 0o600); err != nil {
+		return err
+	}
+	return writeFile(certFile, certPEM, 0o644)
+}
+
+// writeFile replaces the file at path in one step, readers never see it
+// half written.
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,103 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssue(t *testing.T) {
+	ca, err := NewAuthority("ca", time.Hour)
+	require.NoError(t, err)
+
+	certPEM, keyPEM, err := ca.Issue(Request{
+		CommonName: "greet",
+		Hosts:      []string{"greet.example.com", "10.0.0.1"},
+		Validity:   24 * time.Hour,
+		Usage:      x509.ExtKeyUsageServerAuth,
+	})
+	require.NoError(t, err)
+
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"greet.example.com"}, cert.DNSNames)
+	require.Len(t, cert.IPAddresses, 1)
+	assert.Equal(t, "10.0.0.1", cert.IPAddresses[0].String())
+	// Capped by the lifetime of the CA.
+	assert.Equal(t, ca.Cert.NotAfter, cert.NotAfter)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca.CertPEM()))
+	_, err = cert.Verify(x509.VerifyOptions{Roots: pool, DNSName: "greet.example.com", KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+	assert.NoError(t, err)
+	_, err = cert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	assert.Error(t, err)
+
+	_, _, err = ca.Issue(Request{Validity: time.Hour})
+	assert.Error(t, err)
+}
+
+func TestLoadAuthority(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+
+	ca, err := NewAuthority("ca", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, ca.WriteFiles(certFile, keyFile))
+
+	fi, err := os.Stat(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	loaded, err := LoadAuthority(certFile, keyFile)
+	require.NoError(t, err)
+	assert.True(t, ca.Cert.Equal(loaded.Cert))
+
+	// Certificates issued by the loaded CA verify against the original.
+	require.NoError(t, loaded.IssueFiles(Request{CommonName: "greet-client", Validity: time.Hour, Usage: x509.ExtKeyUsageClientAuth},
+		filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.pem")))
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.pem"))
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+	assert.NoError(t, cert.CheckSignatureFrom(ca.Cert))
+
+	_, err = LoadAuthority(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.pem"))
+	assert.ErrorContains(t, err, "not a CA")
+}
+
+func TestKeyPairReloadsAfterInterval(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.pem")
+
+	ca, err := NewAuthority("ca", time.Hour)
+	require.NoError(t, err)
+	req := Request{CommonName: "localhost", Validity: time.Hour, Usage: x509.ExtKeyUsageServerAuth}
+	require.NoError(t, ca.IssueFiles(req, certFile, keyFile))
+
+	k, err := LoadKeyPair(certFile, keyFile, 50*time.Millisecond)
+	require.NoError(t, err)
+	first, err := k.GetCertificate(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, ca.IssueFiles(req, certFile, keyFile))
+
+	// Not before the interval is over.
+	cert, err := k.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, first, cert)
+
+	time.Sleep(50 * time.Millisecond)
+	cert, err = k.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Certificate[0], cert.Certificate[0])
+}
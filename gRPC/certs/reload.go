@@ -0,0 +1,98 @@
+package certs
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// KeyPair is a certificate and its key loaded from files, and loaded
+// again when the files change, so that a server picks up a renewed
+// certificate without restarting. Handshakes done before a reload keep
+// their certificate: open connections are not affected.
+type KeyPair struct {
+	certFile, keyFile string
+	// interval is the least time between two looks at the files.
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	stamp   [2]time.Time
+	checked time.Time
+}
+
+// LoadKeyPair loads the certificate and key at certFile and keyFile,
+// looking for changes at most every interval.
+func LoadKeyPair(certFile, keyFile string, interval time.Duration) (*KeyPair, error) {
+	k := &KeyPair{certFile: certFile, keyFile: keyFile, interval: interval}
+
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// GetCertificate returns the current certificate, reloading it first if
+// the files changed. It is meant for tls.Config.GetCertificate. When the
+// files cannot be loaded, like between the writes of the key and the
+// certificate, the previous certificate is used until the next look.
+func (k *KeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if time.Since(k.checked) >= k.interval {
+		if err := k.reload(); err != nil {
+			log.Printf("Error while reloading the certificate %s: %v\n", k.certFile, err)
+		}
+	}
+	return k.cert, nil
+}
+
+// reload loads the files again when their modification times changed.
+func (k *KeyPair) reload() error {
+	stamp, err := k.modTimes()
+	k.checked = time.Now()
+
+	if err != nil || stamp == k.stamp {
+		return err
+	}
+
+	if err := k.load(); err != nil {
+		return err
+	}
+	log.Printf("Reloaded the certificate %s\n", k.certFile)
+	return nil
+}
+
+func (k *KeyPair) load() error {
+	stamp, err := k.modTimes()
+
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(k.certFile, k.keyFile)
+
+	if err != nil {
+		return fmt.Errorf("loading the certificate: %w", err)
+	}
+
+	k.cert, k.stamp, k.checked = &cert, stamp, time.Now()
+	return nil
+}
+
+func (k *KeyPair) modTimes() ([2]time.Time, error) {
+	var stamp [2]time.Time
+	for i, path := range []string{k.certFile, k.keyFile} {
+		fi, err := os.Stat(path)
+
+		if err != nil {
+			return stamp, err
+		}
+		stamp[i] = fi.ModTime()
+	}
+	return stamp, nil
+}
@@ -0,0 +1,74 @@
+// Package compression registers the message compressors of this module
+// and lets servers pick the one their responses use. Clients choose how
+// requests are compressed with grpc.UseCompressor, and advertise every
+// registered compressor in the grpc-accept-encoding header, so each call
+// negotiates its own compression.
+package compression
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// Compressors. Identity sends messages uncompressed.
+const (
+	Identity = encoding.Identity
+	Gzip     = gzip.Name
+	Zstd     = "zstd"
+)
+
+// Names lists the accepted compressor names.
+var Names = []string{Identity, Gzip, Zstd}
+
+func init() {
+	encoding.RegisterCompressor(newZstdCompressor())
+}
+
+// Check returns an error unless name is empty or one of Names.
+func Check(name string) error {
+	if name != "" && !slices.Contains(Names, name) {
+		return fmt.Errorf("unknown compressor %q, want one of %v", name, Names)
+	}
+	return nil
+}
+
+// CallOptions returns the call options compressing requests with name, or
+// none when name is empty or Identity.
+func CallOptions(name string) []grpc.CallOption {
+	if name == "" || name == Identity {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(name)}
+}
+
+// UnaryServerInterceptor compresses responses with preferred when the
+// client accepts it. Otherwise, and when preferred is empty, responses
+// are compressed like the request.
+func UnaryServerInterceptor(preferred string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		setSendCompressor(ctx, preferred)
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func StreamServerInterceptor(preferred string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		setSendCompressor(ss.Context(), preferred)
+		return handler(srv, ss)
+	}
+}
+
+func setSendCompressor(ctx context.Context, preferred string) {
+	if preferred != "" {
+		// Fails, keeping the compressor of the request, when the client
+		// does not accept preferred.
+		grpc.SetSendCompressor(ctx, preferred)
+	}
+}
@@ -0,0 +1,54 @@
+package compression
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestRoundTrip(t *testing.T) {
+	msg := bytes.Repeat([]byte("Hello Ngoc, number 1 "), 1000)
+
+	for _, name := range []string{Gzip, Zstd} {
+		c := encoding.GetCompressor(name)
+		require.NotNil(t, c, name)
+
+		// Twice, to go through the pooled encoders and decoders.
+		for i := 0; i < 2; i++ {
+			var buf bytes.Buffer
+			w, err := c.Compress(&buf)
+			require.NoError(t, err, name)
+			_, err = w.Write(msg)
+			require.NoError(t, err, name)
+			require.NoError(t, w.Close(), name)
+			assert.Less(t, buf.Len(), len(msg)/10, name)
+
+			r, err := c.Decompress(&buf)
+			require.NoError(t, err, name)
+			got, err := io.ReadAll(r)
+			require.NoError(t, err, name)
+			assert.Equal(t, msg, got, name)
+		}
+	}
+}
+
+func TestZstdRejectsGarbage(t *testing.T) {
+	r, err := encoding.GetCompressor(Zstd).Decompress(bytes.NewReader([]byte("not zstd")))
+	if err == nil {
+		_, err = io.ReadAll(r)
+	}
+	assert.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	for _, name := range []string{"", Identity, Gzip, Zstd} {
+		assert.NoError(t, Check(name), name)
+	}
+	assert.Error(t, Check("brotli"))
+	assert.Empty(t, CallOptions(Identity))
+	assert.Len(t, CallOptions(Zstd), 1)
+}
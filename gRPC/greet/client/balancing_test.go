@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/lb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// replica is a greet server answering with its own name. Its calls block
+// while hold is closed.
+type replica struct {
+	pb.UnimplementedGreetServiceServer
+	name   string
+	addr   string
+	server *grpc.Server
+	health *health.Server
+
+	mu    sync.Mutex
+	calls int
+	hold  chan struct{}
+}
+
+func (r *replica) Greet(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
+	r.mu.Lock()
+	r.calls++
+	hold := r.hold
+	r.mu.Unlock()
+
+	if hold != nil {
+		select {
+		case <-hold:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &pb.GreetResponse{Result: r.name}, nil
+}
+
+func (r *replica) Calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func startReplicas(t *testing.T, n int) []*replica {
+	t.Helper()
+
+	replicas := make([]*replica, n)
+	for i := range replicas {
+		lis, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+
+		r := &replica{
+			name:   "replica-" + string(rune('a'+i)),
+			addr:   lis.Addr().String(),
+			server: grpc.NewServer(),
+			health: health.NewServer(),
+		}
+		pb.RegisterGreetServiceServer(r.server, r)
+		healthpb.RegisterHealthServer(r.server, r.health)
+		r.health.SetServingStatus(pb.GreetService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+		go r.server.Serve(lis)
+		t.Cleanup(r.server.Stop)
+		replicas[i] = r
+	}
+	return replicas
+}
+
+func dialReplicas(t *testing.T, replicas []*replica, balancer string) pb.GreetServiceClient {
+	t.Helper()
+
+	addrs := make([]string, len(replicas))
+	for i, r := range replicas {
+		addrs[i] = r.addr
+	}
+
+	conn, err := dial(config{addr: strings.Join(addrs, ","), balancer: balancer})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	// Wait for every replica to be connected.
+	c := pb.NewGreetServiceClient(conn)
+	require.Eventually(t, func() bool {
+		seen := map[string]bool{}
+		for i := 0; i < 3*len(replicas); i++ {
+			res, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"}, grpc.WaitForReady(true))
+			if err == nil {
+				seen[res.Result] = true
+			}
+		}
+		return len(seen) == len(replicas)
+	}, 5*time.Second, 50*time.Millisecond)
+	return c
+}
+
+// greetN makes n calls and counts the answers of each replica.
+func greetN(t *testing.T, c pb.GreetServiceClient, n int) map[string]int {
+	t.Helper()
+
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		res, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+		require.NoError(t, err)
+		counts[res.Result]++
+	}
+	return counts
+}
+
+// waitGone waits until the calls made by c stop reaching replica name.
+func waitGone(t *testing.T, c pb.GreetServiceClient, name string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		for i := 0; i < 6; i++ {
+			res, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+			if err != nil || res.Result == name {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRoundRobinDistributesAndFailsOver(t *testing.T) {
+	replicas := startReplicas(t, 3)
+	c := dialReplicas(t, replicas, "round_robin")
+
+	assert.Equal(t, map[string]int{"replica-a": 10, "replica-b": 10, "replica-c": 10}, greetN(t, c, 30))
+
+	replicas[1].server.Stop()
+	waitGone(t, c, "replica-b")
+
+	assert.Equal(t, map[string]int{"replica-a": 10, "replica-c": 10}, greetN(t, c, 20))
+}
+
+func TestUnhealthyReplicasGetNoTraffic(t *testing.T) {
+	replicas := startReplicas(t, 3)
+	c := dialReplicas(t, replicas, "round_robin")
+
+	replicas[2].health.SetServingStatus(pb.GreetService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	waitGone(t, c, "replica-c")
+
+	assert.Equal(t, map[string]int{"replica-a": 10, "replica-b": 10}, greetN(t, c, 20))
+
+	replicas[2].health.SetServingStatus(pb.GreetService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	require.Eventually(t, func() bool {
+		return greetN(t, c, 6)["replica-c"] > 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestLeastOutstandingAvoidsBusyReplicas(t *testing.T) {
+	replicas := startReplicas(t, 3)
+	c := dialReplicas(t, replicas, lb.LeastOutstanding)
+
+	// Get a call stuck on replica-a, the calls sent to the other replicas
+	// return right away.
+	hold := make(chan struct{})
+	replicas[0].mu.Lock()
+	replicas[0].hold = hold
+	replicas[0].mu.Unlock()
+
+	var wg sync.WaitGroup
+	for base := replicas[0].Calls(); replicas[0].Calls() == base; {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+		}()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	before := replicas[0].Calls()
+	counts := greetN(t, c, 20)
+	assert.Zero(t, counts["replica-a"])
+	assert.Equal(t, 20, counts["replica-b"]+counts["replica-c"])
+	assert.Equal(t, before, replicas[0].Calls())
+
+	close(hold)
+	wg.Wait()
+
+	replicas[1].server.Stop()
+	waitGone(t, c, "replica-b")
+
+	counts = greetN(t, c, 20)
+	assert.Zero(t, counts["replica-b"])
+	assert.Equal(t, 20, counts["replica-a"]+counts["replica-c"])
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// benchConfig describes a load test of one kind of RPC.
+type benchConfig struct {
+	rpc         string
+	concurrency int
+	duration    time.Duration
+	// messages is the number of messages per stream.
+	messages int
+	payload  int
+}
+
+// benchRPCs are the kinds of RPC a load test can use.
+var benchRPCs = map[string]func(ctx context.Context, c pb.GreetServiceClient, cfg benchConfig, r *benchRecorder) error{
+	"unary":         benchUnary,
+	"client-stream": benchClientStream,
+	"server-stream": benchServerStream,
+	"bidi":          benchBidi,
+}
+
+func parseBenchConfig(args []string) (benchConfig, error) {
+	var cfg benchConfig
+
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.StringVar(&cfg.rpc, "rpc", "unary", "unary (Greet), client-stream (LongGreet), server-stream (GreetManyTimes) or bidi (GreetEveryone)")
+	fs.IntVar(&cfg.concurrency, "c", 4, "concurrent workers")
+	fs.DurationVar(&cfg.duration, "d", 10*time.Second, "duration of the test")
+	fs.IntVar(&cfg.messages, "n", 10, "messages per stream")
+	fs.IntVar(&cfg.payload, "payload", 0, "payload size of the server-stream responses, in bytes")
+
+	if err := fs.Parse(args); err != nil {
+		return benchConfig{}, err
+	}
+
+	if _, ok := benchRPCs[cfg.rpc]; !ok {
+		return benchConfig{}, fmt.Errorf("unknown rpc %q", cfg.rpc)
+	}
+	if cfg.concurrency < 1 || cfg.messages < 1 {
+		return benchConfig{}, errors.New("-c and -n must be at least 1")
+	}
+	return cfg, nil
+}
+
+// benchResult is what a load test measured. Latencies are per RPC, except
+// for bidi where they are per request and response round trip.
+type benchResult struct {
+	rpcs      int
+	messages  int
+	bytes     int
+	errors    int
+	elapsed   time.Duration
+	latencies []time.Duration
+}
+
+type benchRecorder struct {
+	mu sync.Mutex
+	benchResult
+}
+
+func (r *benchRecorder) record(latency time.Duration, messages, bytes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rpcs++
+	r.messages += messages
+	r.bytes += bytes
+	r.latencies = append(r.latencies, latency)
+}
+
+func (r *benchRecorder) fail() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors++
+}
+
+// runBench runs cfg.concurrency workers making RPCs for cfg.duration.
+// Failed RPCs are counted, the test goes on.
+func runBench(c pb.GreetServiceClient, cfg benchConfig) benchResult {
+	run := benchRPCs[cfg.rpc]
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.duration)
+	defer cancel()
+
+	var r benchRecorder
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < cfg.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				err := run(ctx, c, cfg, &r)
+
+				// RPCs cut short by the end of the test do not count.
+				if err != nil && ctx.Err() == nil {
+					r.fail()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	r.elapsed = time.Since(start)
+	return r.benchResult
+}
+
+func benchUnary(ctx context.Context, c pb.GreetServiceClient, _ benchConfig, r *benchRecorder) error {
+	start := time.Now()
+	res, err := c.Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+
+	if err != nil {
+		return err
+	}
+	r.record(time.Since(start), 1, len(res.Result))
+	return nil
+}
+
+func benchClientStream(ctx context.Context, c pb.GreetServiceClient, cfg benchConfig, r *benchRecorder) error {
+	start := time.Now()
+	stream, err := c.LongGreet(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < cfg.messages; i++ {
+		if err := stream.Send(&pb.GreetRequest{FirstName: "Ngoc"}); err != nil {
+			_, err = stream.CloseAndRecv()
+			return err
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+
+	if err != nil {
+		return err
+	}
+	r.record(time.Since(start), cfg.messages, len(res.Result))
+	return nil
+}
+
+func benchServerStream(ctx context.Context, c pb.GreetServiceClient, cfg benchConfig, r *benchRecorder) error {
+	start := time.Now()
+	stream, err := c.GreetManyTimes(ctx, &pb.GreetRequest{
+		FirstName:   "Ngoc",
+		Count:       uint32(cfg.messages),
+		Interval:    durationpb.New(0),
+		PayloadSize: uint32(cfg.payload),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	messages, bytes := 0, 0
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+		messages++
+		bytes += len(res.Result) + len(res.Payload)
+	}
+
+	r.record(time.Since(start), messages, bytes)
+	return nil
+}
+
+func benchBidi(ctx context.Context, c pb.GreetServiceClient, cfg benchConfig, r *benchRecorder) error {
+	stream, err := c.GreetEveryone(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < cfg.messages; i++ {
+		start := time.Now()
+
+		if err := stream.Send(&pb.GreetRequest{FirstName: "Ngoc"}); err != nil {
+			_, err = stream.Recv()
+			return err
+		}
+
+		res, err := stream.Recv()
+
+		if err != nil {
+			return err
+		}
+		r.record(time.Since(start), 1, len(res.Result))
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	if _, err := stream.Recv(); err != io.EOF {
+		return status.Errorf(codes.Internal, "stream not closed by the server: %v", err)
+	}
+	return nil
+}
+
+// report writes the throughput and latency distribution of r to w.
+func (r benchResult) report(w io.Writer, cfg benchConfig) {
+	seconds := r.elapsed.Seconds()
+	fmt.Fprintf(w, "%s: %d workers for %v\n", cfg.rpc, cfg.concurrency, r.elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "  rpcs:       %d (%.1f/s), %d errors\n", r.rpcs, float64(r.rpcs)/seconds, r.errors)
+	fmt.Fprintf(w, "  messages:   %d (%.1f/s)\n", r.messages, float64(r.messages)/seconds)
+	fmt.Fprintf(w, "  throughput: %.1f KiB/s\n", float64(r.bytes)/1024/seconds)
+
+	if len(r.latencies) == 0 {
+		return
+	}
+
+	sorted := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	fmt.Fprintf(w, "  latency:    p50 %v, p90 %v, p99 %v, max %v\n",
+		percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99), sorted[len(sorted)-1])
+}
+
+// percentile returns the p-th percentile of sorted, with the nearest rank
+// method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
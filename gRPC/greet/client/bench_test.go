@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// benchServer answers the RPCs of the load test as fast as it can.
+type benchServer struct {
+	pb.UnimplementedGreetServiceServer
+}
+
+func (benchServer) Greet(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
+	return &pb.GreetResponse{Result: "Hello " + in.FirstName}, nil
+}
+
+func (benchServer) LongGreet(stream pb.GreetService_LongGreetServer) error {
+	res := ""
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.GreetResponse{Result: res})
+		}
+		if err != nil {
+			return err
+		}
+		res += "Hello " + req.FirstName + "!\n"
+	}
+}
+
+func (benchServer) GreetManyTimes(in *pb.GreetRequest, stream pb.GreetService_GreetManyTimesServer) error {
+	for i := uint32(0); i < in.Count; i++ {
+		res := &pb.GreetResponse{Result: "Hello " + in.FirstName, Payload: make([]byte, in.PayloadSize)}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (benchServer) GreetEveryone(stream pb.GreetService_GreetEveryoneServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.GreetResponse{Result: "Hello " + req.FirstName + "!"}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestRunBench(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterGreetServiceServer(s, benchServer{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	c := pb.NewGreetServiceClient(conn)
+
+	for _, rpc := range []string{"unary", "client-stream", "server-stream", "bidi"} {
+		t.Run(rpc, func(t *testing.T) {
+			cfg, err := parseBenchConfig([]string{"-rpc", rpc, "-c", "4", "-d", "200ms", "-n", "5", "-payload", "64"})
+			require.NoError(t, err)
+
+			r := runBench(c, cfg)
+			assert.Zero(t, r.errors)
+			require.NotZero(t, r.rpcs)
+			assert.Len(t, r.latencies, r.rpcs)
+			assert.GreaterOrEqual(t, r.elapsed, 200*time.Millisecond)
+
+			switch rpc {
+			case "unary", "bidi":
+				assert.Equal(t, r.rpcs, r.messages)
+			default:
+				assert.Equal(t, 5*r.rpcs, r.messages)
+			}
+
+			var out bytes.Buffer
+			r.report(&out, cfg)
+			assert.Contains(t, out.String(), "p99")
+		})
+	}
+}
+
+func TestParseBenchConfig(t *testing.T) {
+	_, err := parseBenchConfig([]string{"-rpc", "telepathy"})
+	assert.ErrorContains(t, err, "unknown rpc")
+
+	_, err = parseBenchConfig([]string{"-c", "0"})
+	assert.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i))
+	}
+	assert.Equal(t, time.Duration(50), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(99), percentile(sorted, 99))
+	assert.Equal(t, time.Duration(1), percentile(sorted[:1], 99))
+}
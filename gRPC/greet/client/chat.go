@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+)
+
+// doChat joins room as name and sends every line read from in to it,
+// writing the events of the room to out. A "/join <room>" line moves to
+// another room and "/quit", like the end of in, leaves.
+func doChat(c pb.GreetServiceClient, room, name string, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.Chat(ctx)
+
+	if err != nil {
+		return fmt.Errorf("opening chat stream: %w", err)
+	}
+
+	join := func(room string) error {
+		return stream.Send(&pb.ChatRequest{Action: &pb.ChatRequest_Join{Join: &pb.JoinRoom{Room: room, Name: name}}})
+	}
+
+	if err := join(room); err != nil {
+		return fmt.Errorf("joining %s: %w", room, err)
+	}
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			ev, err := stream.Recv()
+
+			if err == io.EOF {
+				recvErr <- nil
+				return
+			}
+
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			fmt.Fprintln(out, formatChatEvent(ev))
+		}
+	}()
+
+	lines := bufio.NewScanner(in)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+
+		switch {
+		case line == "":
+			continue
+		case line == "/quit":
+		case strings.HasPrefix(line, "/join "):
+			err = join(strings.TrimSpace(strings.TrimPrefix(line, "/join ")))
+		default:
+			err = stream.Send(&pb.ChatRequest{Action: &pb.ChatRequest_Text{Text: line}})
+		}
+
+		if err != nil || line == "/quit" {
+			break
+		}
+	}
+
+	// A failed Send means the stream is over, Recv tells why.
+	stream.CloseSend()
+	return <-recvErr
+}
+
+func formatChatEvent(ev *pb.ChatEvent) string {
+	at := ev.Time.AsTime().Local().Format("15:04:05")
+
+	switch ev.Type {
+	case pb.ChatEvent_TYPE_JOINED:
+		return fmt.Sprintf("%s [%s] * %s joined", at, ev.Room, ev.Name)
+	case pb.ChatEvent_TYPE_LEFT:
+		return fmt.Sprintf("%s [%s] * %s left", at, ev.Room, ev.Name)
+	}
+	return fmt.Sprintf("%s [%s] <%s> %s", at, ev.Room, ev.Name, ev.Text)
+}
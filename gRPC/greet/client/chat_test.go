@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mxngocqb/Golang/gRPC/greet/greettest"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// echoChat answers joins and texts with the matching events, in a single
+// room per stream.
+type echoChat struct {
+	pb.UnimplementedGreetServiceServer
+}
+
+func (echoChat) Chat(stream pb.GreetService_ChatServer) error {
+	var room, name string
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ev := &pb.ChatEvent{Time: timestamppb.Now()}
+		switch action := req.Action.(type) {
+		case *pb.ChatRequest_Join:
+			if room != "" {
+				stream.Send(&pb.ChatEvent{Type: pb.ChatEvent_TYPE_LEFT, Room: room, Name: name, Time: ev.Time})
+			}
+			room, name = action.Join.Room, action.Join.Name
+			ev.Type = pb.ChatEvent_TYPE_JOINED
+		case *pb.ChatRequest_Text:
+			ev.Type, ev.Text = pb.ChatEvent_TYPE_MESSAGE, action.Text
+		}
+		ev.Room, ev.Name = room, name
+
+		if err := stream.Send(ev); err != nil {
+			return err
+		}
+	}
+}
+
+func TestDoChat(t *testing.T) {
+	c, _ := greettest.Start(t, func(s *grpc.Server) {
+		pb.RegisterGreetServiceServer(s, echoChat{})
+	})
+
+	var out bytes.Buffer
+	in := strings.NewReader("hello\n\n/join kitchen\nanyone?\n/quit\nnot sent\n")
+	require.NoError(t, doChat(c, "lobby", "ngoc", in, &out))
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		// Drop the time.
+		lines = append(lines, line[strings.Index(line, " ")+1:])
+	}
+	assert.Equal(t, []string{
+		"[lobby] * ngoc joined",
+		"[lobby] <ngoc> hello",
+		"[lobby] * ngoc left",
+		"[kitchen] * ngoc joined",
+		"[kitchen] <ngoc> anyone?",
+	}, lines)
+}
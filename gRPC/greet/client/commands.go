@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"google.golang.org/grpc"
+)
+
+type command struct {
+	usage string
+	run   func(conn *grpc.ClientConn, args []string) error
+}
+
+func greetCommand(usage string, do func(c pb.GreetServiceClient)) command {
+	return command{
+		usage: usage,
+		run: func(conn *grpc.ClientConn, _ []string) error {
+			do(pb.NewGreetServiceClient(conn))
+			return nil
+		},
+	}
+}
+
+var commands = map[string]command{
+	"greet":          greetCommand("call Greet", doGreet),
+	"greet-many":     greetCommand("call GreetManyTimes", doGreetManyTime),
+	"long-greet":     greetCommand("call LongGreet", doLongGreet),
+	"greet-everyone": greetCommand("call GreetEveryone", doGreetEveryon),
+	"greet-deadline": greetCommand("call GreetWithDeadline with a 1s deadline", func(c pb.GreetServiceClient) {
+		doGreetWithDeadline(c, time.Second)
+	}),
+	"bench": {
+		usage: "[-rpc unary|client-stream|server-stream|bidi] [-c workers] [-d duration] [-n messages] [-payload bytes] load test, best run against a server started with -limits none",
+		run: func(conn *grpc.ClientConn, args []string) error {
+			cfg, err := parseBenchConfig(args)
+
+			if err != nil {
+				return err
+			}
+			runBench(pb.NewGreetServiceClient(conn), cfg).report(os.Stdout, cfg)
+			return nil
+		},
+	},
+	"chat": {
+		usage: "<room> <name> chat interactively, /join <room> to move, /quit to leave",
+		run: func(conn *grpc.ClientConn, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("usage: chat <room> <name>")
+			}
+			return doChat(pb.NewGreetServiceClient(conn), args[0], args[1], os.Stdin, os.Stdout)
+		},
+	},
+	"healthcheck": {
+		usage: "[service] exit non-zero unless the server, or service, is SERVING",
+		run: func(conn *grpc.ClientConn, args []string) error {
+			service := ""
+			if len(args) > 0 {
+				service = args[0]
+			}
+			return doHealthCheck(conn, service)
+		},
+	},
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupCommand(name string) (command, error) {
+	cmd, ok := commands[name]
+
+	if !ok {
+		return command{}, fmt.Errorf("unknown command %q", name)
+	}
+	return cmd, nil
+}
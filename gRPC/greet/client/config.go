@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/mxngocqb/Golang/gRPC/compression"
+	"github.com/mxngocqb/Golang/gRPC/lb"
+	"github.com/mxngocqb/Golang/gRPC/tracing"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+type config struct {
+	addr       string
+	tls        bool
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+	token      string
+	tokenFile  string
+
+	serviceConfigFile string
+	balancer          string
+	compression       string
+
+	tracing tracing.Config
+	baggage baggage.Baggage
+
+	command string
+	args    []string
+}
+
+// parseConfig reads the client configuration from args. Every flag
+// defaults to the value of its GREET_* environment variable, so flags
+// override the environment. The first argument after the flags names the
+// command to run, "greet" by default.
+func parseConfig(args []string) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("client", flag.ContinueOnError)
+	fs.StringVar(&cfg.addr, "addr", envString("GREET_ADDR", "localhost:50051"), "server address, or comma separated replica addresses, or a target like dns:///host:port [GREET_ADDR]")
+	fs.BoolVar(&cfg.tls, "tls", envBool("GREET_TLS", true), "connect over TLS [GREET_TLS]")
+	fs.StringVar(&cfg.caFile, "ca", envString("GREET_CA", "ssl/ca.crt"), "CA verifying the server certificate [GREET_CA]")
+	fs.StringVar(&cfg.certFile, "cert", envString("GREET_CLIENT_CERT", ""), "client certificate for mTLS [GREET_CLIENT_CERT]")
+	fs.StringVar(&cfg.keyFile, "key", envString("GREET_CLIENT_KEY", ""), "client private key for mTLS [GREET_CLIENT_KEY]")
+	fs.StringVar(&cfg.serverName, "server-name", envString("GREET_SERVER_NAME", ""), "override the name checked against the server certificate [GREET_SERVER_NAME]")
+	fs.StringVar(&cfg.token, "token", envString("GREET_TOKEN", ""), "bearer token sent with every RPC [GREET_TOKEN]")
+	fs.StringVar(&cfg.tokenFile, "token-file", envString("GREET_TOKEN_FILE", ""), "file holding the bearer token sent with every RPC [GREET_TOKEN_FILE]")
+	fs.StringVar(&cfg.serviceConfigFile, "service-config", envString("GREET_SERVICE_CONFIG", ""), "JSON service config replacing the built-in deadlines, retry and hedging policies [GREET_SERVICE_CONFIG]")
+	fs.StringVar(&cfg.balancer, "lb", envString("GREET_LB", "round_robin"), "load balancing policy across replicas: round_robin, "+lb.LeastOutstanding+" or pick_first [GREET_LB]")
+	fs.StringVar(&cfg.compression, "compression", envString("GREET_COMPRESSION", compression.Identity), "compressor of the requests: identity, gzip or zstd. Responses are compressed as the server chooses [GREET_COMPRESSION]")
+	fs.StringVar(&cfg.tracing.Exporter, "trace", envString("GREET_TRACE", tracing.None), "where to export traces: none, stdout or otlp [GREET_TRACE]")
+	fs.StringVar(&cfg.tracing.OTLPEndpoint, "otlp-endpoint", envString("GREET_OTLP_ENDPOINT", ""), "host:port of the OTLP gRPC collector, see also OTEL_EXPORTER_OTLP_ENDPOINT [GREET_OTLP_ENDPOINT]")
+	fs.BoolVar(&cfg.tracing.OTLPInsecure, "otlp-insecure", envBool("GREET_OTLP_INSECURE", false), "connect to the OTLP collector without TLS [GREET_OTLP_INSECURE]")
+	bag := fs.String("baggage", envString("GREET_BAGGAGE", ""), "W3C baggage sent with every RPC, like \"tenant=acme,user=ngoc\" [GREET_BAGGAGE]")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: client [flags] [command] [args]\n\nCommands:\n")
+		for _, name := range commandNames() {
+			fmt.Fprintf(fs.Output(), "  %-16s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintf(fs.Output(), "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	if cfg.token != "" && cfg.tokenFile != "" {
+		return config{}, errors.New("-token and -token-file are mutually exclusive")
+	}
+
+	if err := compression.Check(cfg.compression); err != nil {
+		return config{}, fmt.Errorf("invalid -compression: %w", err)
+	}
+
+	var err error
+	cfg.baggage, err = baggage.Parse(*bag)
+
+	if err != nil {
+		return config{}, fmt.Errorf("invalid -baggage: %w", err)
+	}
+
+	cfg.command = "greet"
+	if fs.NArg() > 0 {
+		cfg.command = fs.Arg(0)
+		cfg.args = fs.Args()[1:]
+	}
+	return cfg, nil
+}
+
+func envString(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/compression"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/lb"
+	"github.com/mxngocqb/Golang/gRPC/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // enables client-side health checking
+	"google.golang.org/grpc/keepalive"
+)
+
+// defaultServiceConfig sets the per-method deadlines, the retry policy of
+// Greet and the hedging policy of GreetWithDeadline.
+//
+//go:embed service_config.json
+var defaultServiceConfig string
+
+// keepaliveParams ping idle connections so that dead servers are noticed
+// before the next call. The server must allow pings this frequent.
+var keepaliveParams = keepalive.ClientParameters{
+	Time:                30 * time.Second,
+	Timeout:             10 * time.Second,
+	PermitWithoutStream: true,
+}
+
+// dial connects to the servers described by cfg, with opts appended to
+// the options returned by dialOptions. cfg.addr is either a comma separated
+// list of endpoints or a single target, like "dns:///greet.internal:50051".
+func dial(cfg config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	target, authority, err := lb.Target(cfg.addr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Every replica has to present a certificate valid for the first
+	// endpoint, unless -server-name says otherwise.
+	if authority != "" && cfg.serverName == "" {
+		opts = append(opts, grpc.WithAuthority(authority))
+	}
+
+	defaults, err := dialOptions(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Dial(target, append(defaults, opts...)...)
+}
+
+// dialOptions returns the options connecting to the servers described by
+// cfg.
+func dialOptions(cfg config) ([]grpc.DialOption, error) {
+	serviceConfig := defaultServiceConfig
+
+	if cfg.serviceConfigFile != "" {
+		b, err := os.ReadFile(cfg.serviceConfigFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading service config: %w", err)
+		}
+		serviceConfig = string(b)
+	}
+
+	serviceConfig, err := withBalancing(serviceConfig, cfg.balancer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hedging, err := hedgingPolicies(serviceConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithKeepaliveParams(keepaliveParams),
+		grpc.WithStatsHandler(tracing.ClientHandler()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientBaggage(cfg.baggage), hedgeUnary(hedging)),
+		grpc.WithChainStreamInterceptor(tracing.StreamClientBaggage(cfg.baggage)),
+	}
+
+	if callOpts := compression.CallOptions(cfg.compression); len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	if cfg.tls {
+		creds, err := clientCredentials(cfg)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	token, err := tokenCredentials(cfg)
+
+	if err != nil {
+		return nil, fmt.Errorf("loading the token: %w", err)
+	}
+
+	if token != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(token))
+	}
+
+	return opts, nil
+}
+
+// withBalancing sets the load balancing policy of serviceConfig to
+// balancer and has it only use the replicas whose grpc.health.v1 service
+// reports the greet service as SERVING. Settings already present in
+// serviceConfig are kept.
+func withBalancing(serviceConfig, balancer string) (string, error) {
+	var sc map[string]json.RawMessage
+
+	if err := json.Unmarshal([]byte(serviceConfig), &sc); err != nil {
+		return "", fmt.Errorf("parsing service config: %w", err)
+	}
+
+	if _, ok := sc["loadBalancingConfig"]; !ok && balancer != "" {
+		lbConfig, err := json.Marshal([]map[string]struct{}{{balancer: {}}})
+
+		if err != nil {
+			return "", err
+		}
+		sc["loadBalancingConfig"] = lbConfig
+	}
+
+	if _, ok := sc["healthCheckConfig"]; !ok {
+		sc["healthCheckConfig"] = json.RawMessage(`{"serviceName": "` + pb.GreetService_ServiceDesc.ServiceName + `"}`)
+	}
+
+	b, err := json.Marshal(sc)
+	return string(b), err
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/greet/greettest"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// faultServer answers each unary call with what fault returns for its
+// attempt number, starting at 0.
+type faultServer struct {
+	pb.UnimplementedGreetServiceServer
+	fault func(ctx context.Context, attempt int) error
+
+	mu       sync.Mutex
+	attempts int
+}
+
+func (s *faultServer) call(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
+	s.mu.Lock()
+	attempt := s.attempts
+	s.attempts++
+	s.mu.Unlock()
+
+	if err := s.fault(ctx, attempt); err != nil {
+		return nil, err
+	}
+	return &pb.GreetResponse{Result: "Hello " + in.FirstName}, nil
+}
+
+func (s *faultServer) Greet(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
+	return s.call(ctx, in)
+}
+
+func (s *faultServer) GreetWithDeadline(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
+	return s.call(ctx, in)
+}
+
+func (s *faultServer) Attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+// failFirst fails the first n attempts with code.
+func failFirst(n int, code codes.Code) func(context.Context, int) error {
+	return func(_ context.Context, attempt int) error {
+		if attempt < n {
+			return status.Error(code, "injected fault")
+		}
+		return nil
+	}
+}
+
+// hang blocks the attempts for which it is true until they are canceled.
+func hang(which func(attempt int) bool) func(context.Context, int) error {
+	return func(ctx context.Context, attempt int) error {
+		if which(attempt) {
+			<-ctx.Done()
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return nil
+	}
+}
+
+// dialFaultServer connects to srv over bufconn with the options the client
+// dials with, using serviceConfig instead of the built-in one when set.
+func dialFaultServer(t *testing.T, srv pb.GreetServiceServer, serviceConfig string) pb.GreetServiceClient {
+	t.Helper()
+
+	cfg := config{}
+	if serviceConfig != "" {
+		cfg.serviceConfigFile = filepath.Join(t.TempDir(), "service_config.json")
+		require.NoError(t, os.WriteFile(cfg.serviceConfigFile, []byte(serviceConfig), 0o600))
+	}
+
+	opts, err := dialOptions(cfg)
+	require.NoError(t, err)
+
+	c, _ := greettest.Start(t, func(s *grpc.Server) {
+		pb.RegisterGreetServiceServer(s, srv)
+	}, greettest.WithDialOptions(opts...))
+	return c
+}
+
+func TestGreetRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		fault    func(context.Context, int) error
+		code     codes.Code
+		attempts int
+	}{
+		{"unavailable", failFirst(2, codes.Unavailable), codes.OK, 3},
+		{"resource exhausted", failFirst(1, codes.ResourceExhausted), codes.OK, 2},
+		{"gives up", failFirst(10, codes.Unavailable), codes.Unavailable, 4},
+		{"not retryable", failFirst(1, codes.InvalidArgument), codes.InvalidArgument, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &faultServer{fault: tt.fault}
+			c := dialFaultServer(t, srv, "")
+
+			res, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+			assert.Equal(t, tt.code, status.Code(err))
+			if err == nil {
+				assert.Equal(t, "Hello Ngoc", res.Result)
+			}
+			assert.Equal(t, tt.attempts, srv.Attempts())
+		})
+	}
+}
+
+const fastHedgingConfig = `{
+  "methodConfig": [{
+    "name": [{"service": "greet.GreetService", "method": "GreetWithDeadline"}],
+    "hedgingPolicy": {
+      "maxAttempts": 3,
+      "hedgingDelay": "0.1s",
+      "nonFatalStatusCodes": ["UNAVAILABLE"]
+    }
+  }, {
+    "name": [{"service": "greet.GreetService", "method": "Greet"}],
+    "timeout": "0.2s"
+  }]
+}`
+
+func TestGreetWithDeadlineHedging(t *testing.T) {
+	t.Run("slow attempt", func(t *testing.T) {
+		srv := &faultServer{fault: hang(func(attempt int) bool { return attempt == 0 })}
+		c := dialFaultServer(t, srv, fastHedgingConfig)
+
+		start := time.Now()
+		res, err := c.GreetWithDeadline(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+		require.NoError(t, err)
+		assert.Equal(t, "Hello Ngoc", res.Result)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, 2, srv.Attempts())
+	})
+
+	t.Run("non-fatal error", func(t *testing.T) {
+		srv := &faultServer{fault: failFirst(2, codes.Unavailable)}
+		c := dialFaultServer(t, srv, fastHedgingConfig)
+
+		start := time.Now()
+		_, err := c.GreetWithDeadline(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+		assert.Equal(t, 3, srv.Attempts())
+	})
+
+	t.Run("fatal error", func(t *testing.T) {
+		srv := &faultServer{fault: failFirst(1, codes.InvalidArgument)}
+		c := dialFaultServer(t, srv, fastHedgingConfig)
+
+		_, err := c.GreetWithDeadline(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, 1, srv.Attempts())
+	})
+
+	t.Run("every attempt hangs", func(t *testing.T) {
+		srv := &faultServer{fault: hang(func(int) bool { return true })}
+		c := dialFaultServer(t, srv, fastHedgingConfig)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		_, err := c.GreetWithDeadline(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.Equal(t, 3, srv.Attempts())
+	})
+}
+
+func TestServiceConfigDeadline(t *testing.T) {
+	srv := &faultServer{fault: hang(func(int) bool { return true })}
+	c := dialFaultServer(t, srv, fastHedgingConfig)
+
+	start := time.Now()
+	_, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestDefaultServiceConfig(t *testing.T) {
+	policies, err := hedgingPolicies(defaultServiceConfig)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]hedgingPolicy{
+		"/greet.GreetService/GreetWithDeadline": {
+			MaxAttempts:         2,
+			HedgingDelay:        jsonDuration(4 * time.Second),
+			NonFatalStatusCodes: []codes.Code{codes.Unavailable},
+		},
+	}, policies)
+
+	// Streams as long as the client asks for, paced by the server, must not
+	// inherit the deadline of the whole service.
+	var sc struct {
+		MethodConfig []struct {
+			Name    []struct{ Service, Method string }
+			Timeout *string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(defaultServiceConfig), &sc))
+	untimed := map[string]bool{}
+	for _, mc := range sc.MethodConfig {
+		for _, name := range mc.Name {
+			if name.Method != "" && mc.Timeout == nil {
+				untimed[name.Method] = true
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{"GreetManyTimes": true, "Chat": true}, untimed)
+
+	// grpc.Dial rejects invalid service configs.
+	c := dialFaultServer(t, &faultServer{fault: failFirst(0, codes.OK)}, "")
+	_, err = c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+	assert.NoError(t, err)
+}
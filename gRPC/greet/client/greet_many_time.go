@@ -4,8 +4,14 @@ import (
 	"context"
 	"io"
 	"log"
+	"math/rand"
+	"time"
 
 	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func doGreetManyTime(c pb.GreetServiceClient) {
@@ -15,23 +21,129 @@ func doGreetManyTime(c pb.GreetServiceClient) {
 		FirstName: "Ngoc",
 	}
 
-	stream, err := c.GreetManyTimes(context.Background(), req)
+	err := greetManyTimes(context.Background(), c, req, defaultResumePolicy, func(msg *pb.GreetResponse) {
+		log.Printf("GreetManyTimes: %s\n", msg.Result)
+	})
 
 	if err != nil {
-		log.Fatalf("Error while calling GreeManyTimes: %v\n", err)
+		log.Fatalf("Error while reading the stream: %v\n", err)
+	}
+}
+
+// resumePolicy says how greetManyTimes reconnects a broken stream.
+type resumePolicy struct {
+	// maxAttempts is the number of attempts in a row that may fail
+	// without receiving any new response.
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// defaultCount is the number of responses the server sends when the
+// request leaves count unset.
+const defaultCount = 10
+
+var defaultResumePolicy = resumePolicy{
+	maxAttempts:    5,
+	initialBackoff: 100 * time.Millisecond,
+	maxBackoff:     5 * time.Second,
+}
+
+// greetManyTimes calls GreetManyTimes and passes the responses to deliver
+// in order, exactly once each. When the stream breaks with a transient
+// error, it reconnects and resumes after the last response delivered.
+// A stream breaking once every response was delivered is not resumed.
+func greetManyTimes(ctx context.Context, c pb.GreetServiceClient, req *pb.GreetRequest, p resumePolicy, deliver func(*pb.GreetResponse)) error {
+	req = proto.Clone(req).(*pb.GreetRequest)
+	next := req.ResumeFrom
+	backoff := p.initialBackoff
+
+	count := req.Count
+	if count == 0 {
+		count = defaultCount
+	}
+
+	for attempt := 1; ; attempt++ {
+		req.ResumeFrom = next
+		progress, err := receiveFrom(ctx, c, req, &next, deliver)
+
+		if err == nil || next >= count {
+			return nil
+		}
+
+		if progress {
+			attempt, backoff = 1, p.initialBackoff
+		}
+
+		retryAfter, ok := resumable(err)
+
+		if !ok || attempt >= p.maxAttempts {
+			return err
+		}
+
+		// Full jitter, as the retries of grpc-go, unless the server
+		// asked for a longer pause.
+		delay := max(time.Duration(rand.Int63n(int64(backoff)+1)), retryAfter)
+		backoff = min(2*backoff, p.maxBackoff)
+		log.Printf("GreetManyTimes broke before response %d, resuming in %v: %v\n", next, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// receiveFrom makes one GreetManyTimes call for the responses from *next
+// on, and delivers them, moving *next along. Responses delivered before
+// are skipped. progress reports whether anything was delivered.
+func receiveFrom(ctx context.Context, c pb.GreetServiceClient, req *pb.GreetRequest, next *uint32, deliver func(*pb.GreetResponse)) (progress bool, err error) {
+	stream, err := c.GreetManyTimes(ctx, req)
+
+	if err != nil {
+		return false, err
 	}
 
 	for {
-		msg, err := stream.Recv()
+		res, err := stream.Recv()
 
 		if err == io.EOF {
-			break
+			return progress, nil
 		}
 
 		if err != nil {
-			log.Fatalf("Error while reading the stream: %v\n", err)
+			return progress, err
 		}
 
-		log.Printf("GreetManyTimes: %s\n", msg.Result)
+		switch {
+		case res.Sequence < *next:
+			continue
+		case res.Sequence > *next:
+			return progress, status.Errorf(codes.DataLoss, "expected response %d, got %d", *next, res.Sequence)
+		}
+
+		deliver(res)
+		*next++
+		progress = true
+	}
+}
+
+// resumable reports whether a stream broken by err can be resumed, and
+// how long the server asked to wait before that, if it did.
+func resumable(err error) (retryAfter time.Duration, ok bool) {
+	st := status.Convert(err)
+
+	switch st.Code() {
+	case codes.Unavailable, codes.Aborted:
+		return 0, true
+	case codes.ResourceExhausted:
+		// Only rate limiting is transient, not messages too large.
+		for _, d := range st.Details() {
+			if info, ok := d.(*errdetails.RetryInfo); ok {
+				return info.RetryDelay.AsDuration(), true
+			}
+		}
 	}
+	return 0, false
 }
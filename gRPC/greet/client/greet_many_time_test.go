@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// breakingStream serves GreetManyTimes, breaking the n-th call after
+// breaks[n] responses with err. It starts one response before resume_from
+// so that clients see duplicates.
+type breakingStream struct {
+	pb.UnimplementedGreetServiceServer
+	breaks []int
+	err    error
+
+	mu      sync.Mutex
+	resumes []uint32
+}
+
+func (s *breakingStream) GreetManyTimes(in *pb.GreetRequest, stream pb.GreetService_GreetManyTimesServer) error {
+	s.mu.Lock()
+	call := len(s.resumes)
+	s.resumes = append(s.resumes, in.ResumeFrom)
+	s.mu.Unlock()
+
+	from := in.ResumeFrom
+	if from > 0 {
+		from--
+	}
+
+	for i, sent := from, 0; ; i, sent = i+1, sent+1 {
+		if call < len(s.breaks) && sent == s.breaks[call] {
+			return s.err
+		}
+
+		if i >= in.Count {
+			return nil
+		}
+
+		res := &pb.GreetResponse{Result: fmt.Sprintf("Hello %s, number %d", in.FirstName, i), Sequence: i}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *breakingStream) Resumes() []uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]uint32(nil), s.resumes...)
+}
+
+var fastResume = resumePolicy{maxAttempts: 3, initialBackoff: time.Millisecond, maxBackoff: 10 * time.Millisecond}
+
+func collect(t *testing.T, c pb.GreetServiceClient, count uint32, p resumePolicy) ([]uint32, error) {
+	t.Helper()
+
+	var got []uint32
+	err := greetManyTimes(context.Background(), c, &pb.GreetRequest{FirstName: "Ngoc", Count: count}, p, func(res *pb.GreetResponse) {
+		got = append(got, res.Sequence)
+	})
+	return got, err
+}
+
+func sequence(from, to uint32) []uint32 {
+	var s []uint32
+	for i := from; i < to; i++ {
+		s = append(s, i)
+	}
+	return s
+}
+
+func TestGreetManyTimesResumes(t *testing.T) {
+	// The first call breaks at response 6, the next two make progress
+	// then break, the last one ends the stream.
+	srv := &breakingStream{breaks: []int{6, 3, 4}, err: status.Error(codes.Unavailable, "connection reset")}
+	c := dialFaultServer(t, srv, "")
+
+	got, err := collect(t, c, 20, fastResume)
+	require.NoError(t, err)
+	assert.Equal(t, sequence(0, 20), got)
+	assert.Equal(t, []uint32{0, 6, 8, 11}, srv.Resumes())
+}
+
+func TestGreetManyTimesBrokenAfterLastResponse(t *testing.T) {
+	// The stream breaks after the last response but before the trailers,
+	// leaving nothing to resume.
+	srv := &breakingStream{breaks: []int{20}, err: status.Error(codes.Unavailable, "connection reset")}
+	c := dialFaultServer(t, srv, "")
+
+	got, err := collect(t, c, 20, fastResume)
+	require.NoError(t, err)
+	assert.Equal(t, sequence(0, 20), got)
+	assert.Equal(t, []uint32{0}, srv.Resumes())
+}
+
+func TestGreetManyTimesGivesUp(t *testing.T) {
+	// After the first response, every call breaks before any new one.
+	srv := &breakingStream{breaks: []int{1, 1, 1, 1, 1}, err: status.Error(codes.Unavailable, "connection reset")}
+	c := dialFaultServer(t, srv, "")
+
+	got, err := collect(t, c, 20, fastResume)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, []uint32{0}, got)
+	assert.Equal(t, []uint32{0, 1, 1}, srv.Resumes())
+}
+
+func TestGreetManyTimesDoesNotResumePermanentErrors(t *testing.T) {
+	srv := &breakingStream{breaks: []int{2}, err: status.Error(codes.InvalidArgument, "no")}
+	c := dialFaultServer(t, srv, "")
+
+	got, err := collect(t, c, 20, fastResume)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, sequence(0, 2), got)
+	assert.Len(t, srv.Resumes(), 1)
+}
+
+func TestResumable(t *testing.T) {
+	_, ok := resumable(status.Error(codes.ResourceExhausted, "message too large"))
+	assert.False(t, ok)
+
+	st, err := status.New(codes.ResourceExhausted, "rate limited").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	require.NoError(t, err)
+	retryAfter, ok := resumable(st.Err())
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	_, ok = resumable(status.Error(codes.DeadlineExceeded, "too slow"))
+	assert.False(t, ok)
+}
@@ -13,7 +13,7 @@ import (
 func doGreetWithDeadline(c pb.GreetServiceClient, timeout time.Duration) {
 	log.Println("doGreetWithDeadline was invoked")
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	req := &pb.GreetRequest{
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const healthCheckTimeout = 5 * time.Second
+
+// doHealthCheck asks the grpc.health.v1 service for the status of service,
+// "" meaning the server as a whole, and fails unless it is SERVING.
+func doHealthCheck(conn grpc.ClientConnInterface, service string) error {
+	log.Printf("doHealthCheck was invoked for %q\n", service)
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	log.Printf("Health: %s\n", res.Status)
+
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("server is %s", res.Status)
+	}
+	return nil
+}
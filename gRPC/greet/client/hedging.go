@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// hedgingPolicy is the hedgingPolicy of a gRPC service config method: up
+// to maxAttempts copies of a call are sent, hedgingDelay apart, and the
+// first successful answer wins. grpc-go ignores it, hedgeUnary implements
+// it for unary calls.
+type hedgingPolicy struct {
+	MaxAttempts         int          `json:"maxAttempts"`
+	HedgingDelay        jsonDuration `json:"hedgingDelay"`
+	NonFatalStatusCodes []codes.Code `json:"nonFatalStatusCodes"`
+}
+
+type jsonDuration time.Duration
+
+func (d *jsonDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(v)
+	return nil
+}
+
+// hedgingPolicies reads the hedging policies of a service config, keyed
+// by full method name, or by "/service/" for a whole service.
+func hedgingPolicies(serviceConfig string) (map[string]hedgingPolicy, error) {
+	var sc struct {
+		MethodConfig []struct {
+			Name []struct {
+				Service string `json:"service"`
+				Method  string `json:"method"`
+			} `json:"name"`
+			HedgingPolicy *hedgingPolicy `json:"hedgingPolicy"`
+		} `json:"methodConfig"`
+	}
+
+	if err := json.Unmarshal([]byte(serviceConfig), &sc); err != nil {
+		return nil, fmt.Errorf("parsing service config: %w", err)
+	}
+
+	policies := map[string]hedgingPolicy{}
+	for _, mc := range sc.MethodConfig {
+		if mc.HedgingPolicy == nil {
+			continue
+		}
+		if mc.HedgingPolicy.MaxAttempts < 2 {
+			return nil, fmt.Errorf("hedgingPolicy.maxAttempts must be at least 2")
+		}
+
+		for _, name := range mc.Name {
+			policies["/"+name.Service+"/"+name.Method] = *mc.HedgingPolicy
+		}
+	}
+	return policies, nil
+}
+
+// hedgeUnary sends the unary calls covered by policies as hedged
+// requests. The call options, like grpc.Header, are shared by every
+// attempt and should not be used with hedged methods.
+func hedgeUnary(policies map[string]hedgingPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		p, ok := policies[method]
+		if !ok {
+			p, ok = policies[method[:len(method)-len(path.Base(method))]]
+		}
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type result struct {
+			reply proto.Message
+			err   error
+		}
+		results := make(chan result, p.MaxAttempts)
+
+		started, pending := 0, 0
+		send := func() {
+			started++
+			pending++
+			go func() {
+				r := reply.(proto.Message).ProtoReflect().New().Interface()
+				err := invoker(ctx, method, req, r, cc, opts...)
+				results <- result{reply: r, err: err}
+			}()
+		}
+
+		timer := time.NewTimer(time.Duration(p.HedgingDelay))
+		defer timer.Stop()
+		sendNext := func() {
+			if started < p.MaxAttempts {
+				send()
+				resetTimer(timer, time.Duration(p.HedgingDelay))
+			}
+		}
+
+		send()
+
+		var err error
+		for pending > 0 {
+			select {
+			case <-timer.C:
+				sendNext()
+			case r := <-results:
+				pending--
+				if r.err == nil {
+					proto.Merge(reply.(proto.Message), r.reply)
+					return nil
+				}
+
+				err = r.err
+				if !p.nonFatal(status.Code(err)) {
+					return err
+				}
+				sendNext()
+			}
+		}
+		return err
+	}
+}
+
+func (p hedgingPolicy) nonFatal(code codes.Code) bool {
+	for _, c := range p.NonFatalStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
@@ -1,34 +1,33 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
-	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/mxngocqb/Golang/gRPC/tracing"
 )
 
-var addr string = "localhost:50051"
-
 func main() {
-	tls := true // change that to true if needed
-	opts := []grpc.DialOption{}
-
-	if tls {
-		certFile := "ssl/ca.crt"
-		creds, err := credentials.NewClientTLSFromFile(certFile, "")
-
-		if err != nil {
-			log.Fatalf("Error while loading CA trust certificate: %v\n", err)
-		}
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		creds := grpc.WithTransportCredentials(insecure.NewCredentials())
-		opts = append(opts, creds)
+	cfg, err := parseConfig(os.Args[1:])
+
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v\n", err)
+	}
+
+	cmd, err := lookupCommand(cfg.command)
+
+	if err != nil {
+		log.Fatalf("Invalid command: %v\n", err)
 	}
 
-	conn, err := grpc.Dial(addr, opts...)
+	shutdownTracing, err := tracing.Setup(context.Background(), "greet-client", cfg.tracing)
+
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v\n", err)
+	}
+
+	conn, err := dial(cfg)
 
 	if err != nil {
 		log.Fatalf("Failed to connect: %v\n", err)
@@ -36,12 +35,12 @@ func main() {
 
 	log.Print(conn.Target())
 
-	defer conn.Close()
-	c := pb.NewGreetServiceClient(conn)
+	err = cmd.run(conn, cfg.args)
+	conn.Close()
+	shutdownTracing(context.Background())
 
-	doGreet(c)
-	// doGreetManyTime(c)
-	// doLongGreet(c)
-	// doGreetEveryon(c)
-	// doGreetWithDeadline(c, 1)
+	if err != nil {
+		log.Printf("%s failed: %v\n", cfg.command, err)
+		os.Exit(1)
+	}
 }
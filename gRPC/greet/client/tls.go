@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mxngocqb/Golang/gRPC/auth"
+	"google.golang.org/grpc/credentials"
+)
+
+// clientCredentials builds the TLS credentials described by cfg. A client
+// certificate is presented when both -cert and -key are set.
+func clientCredentials(cfg config) (credentials.TransportCredentials, error) {
+	pem, err := os.ReadFile(cfg.caFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("loading CA trust certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", cfg.caFile)
+	}
+
+	tlsCfg := &tls.Config{
+		RootCAs:    pool,
+		ServerName: cfg.serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	switch {
+	case cfg.certFile != "" && cfg.keyFile != "":
+		cert, err := tls.LoadX509KeyPair(cfg.certFile, cfg.keyFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	case cfg.certFile != "" || cfg.keyFile != "":
+		return nil, errors.New("-cert and -key must be set together")
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// tokenCredentials returns the bearer token credentials described by cfg,
+// or nil when no token is configured.
+func tokenCredentials(cfg config) (credentials.PerRPCCredentials, error) {
+	var creds auth.TokenCredentials
+
+	switch {
+	case cfg.tokenFile != "":
+		var err error
+		creds, err = auth.TokenFromFile(cfg.tokenFile)
+
+		if err != nil {
+			return nil, err
+		}
+	case cfg.token != "":
+		creds.Token = cfg.token
+	default:
+		return nil, nil
+	}
+
+	creds.Insecure = !cfg.tls
+	return creds, nil
+}
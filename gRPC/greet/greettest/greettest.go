@@ -0,0 +1,143 @@
+// Package greettest runs greet services in process for tests. The server
+// listens on an in-memory bufconn listener, optionally over TLS with
+// certificates generated for the occasion, and the returned client is
+// connected to it.
+package greettest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/certs"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// ServerName is the name the server certificate is issued for with
+// WithTLS.
+const ServerName = "greet.test"
+
+type options struct {
+	tls        bool
+	serverOpts []grpc.ServerOption
+	dialOpts   []grpc.DialOption
+}
+
+// An Option configures Start.
+type Option func(*options)
+
+// WithTLS serves over TLS with a certificate for ServerName, signed by a
+// CA the client trusts. Both are generated by Start.
+func WithTLS() Option {
+	return func(o *options) { o.tls = true }
+}
+
+// WithServerOptions adds options to the server, like interceptors.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(o *options) { o.serverOpts = append(o.serverOpts, opts...) }
+}
+
+// WithDialOptions adds options to the client connection.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) { o.dialOpts = append(o.dialOpts, opts...) }
+}
+
+// Start serves the services register adds to a new server, and returns a
+// client connected to it along with a function closing the connection
+// and stopping the server. The function is also registered with
+// tb.Cleanup, calling it earlier is fine. Start fails tb when the server
+// cannot be set up.
+func Start(tb testing.TB, register func(*grpc.Server), opts ...Option) (pb.GreetServiceClient, func()) {
+	tb.Helper()
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	serverOpts := o.serverOpts
+	clientCreds := insecure.NewCredentials()
+
+	if o.tls {
+		serverCreds, creds, err := generateCredentials()
+
+		if err != nil {
+			tb.Fatalf("generating TLS credentials: %v", err)
+		}
+		serverOpts = append([]grpc.ServerOption{grpc.Creds(serverCreds)}, serverOpts...)
+		clientCreds = creds
+	}
+
+	s := grpc.NewServer(serverOpts...)
+	register(s)
+
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(clientCreds),
+	}, o.dialOpts...)
+
+	conn, err := grpc.Dial("passthrough:///"+ServerName, dialOpts...)
+
+	if err != nil {
+		s.Stop()
+		tb.Fatalf("connecting to the server: %v", err)
+	}
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			conn.Close()
+			s.Stop()
+		})
+	}
+	tb.Cleanup(cleanup)
+
+	return pb.NewGreetServiceClient(conn), cleanup
+}
+
+// generateCredentials returns server credentials for ServerName, and
+// client credentials trusting the CA which signed them.
+func generateCredentials() (server, client credentials.TransportCredentials, err error) {
+	ca, err := certs.NewAuthority("greettest CA", time.Hour)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM, keyPEM, err := ca.Issue(certs.Request{
+		CommonName: ServerName,
+		Hosts:      []string{ServerName},
+		Validity:   time.Hour,
+		Usage:      x509.ExtKeyUsageServerAuth,
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert)
+
+	server = credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	client = credentials.NewTLS(&tls.Config{RootCAs: pool, ServerName: ServerName, MinVersion: tls.VersionTLS12})
+	return server, client, nil
+}
@@ -0,0 +1,76 @@
+package greettest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// echoGreet answers Greet with the security protocol of the connection.
+type echoGreet struct {
+	pb.UnimplementedGreetServiceServer
+}
+
+func (echoGreet) Greet(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
+	protocol := "insecure"
+	if p, ok := peer.FromContext(ctx); ok && p.AuthInfo != nil {
+		protocol = p.AuthInfo.AuthType()
+	}
+	return &pb.GreetResponse{Result: in.FirstName + " over " + protocol}, nil
+}
+
+func register(s *grpc.Server) {
+	pb.RegisterGreetServiceServer(s, echoGreet{})
+}
+
+func TestStart(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"insecure", nil, "Ngoc over insecure"},
+		{"TLS", []Option{WithTLS()}, "Ngoc over tls"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, cleanup := Start(t, register, tc.opts...)
+
+			res, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, res.Result)
+
+			cleanup()
+			cleanup()
+			_, err = c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStartWithOptions(t *testing.T) {
+	var methods []string
+	c, _ := Start(t, register,
+		WithServerOptions(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			methods = append(methods, info.FullMethod)
+			return handler(ctx, req)
+		})),
+		WithDialOptions(grpc.WithUserAgent("greettest")),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := c.Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	_, err = c.GreetWithDeadline(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	assert.Equal(t, []string{"/greet.GreetService/Greet", "/greet.GreetService/GreetWithDeadline"}, methods)
+}
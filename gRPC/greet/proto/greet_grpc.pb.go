@@ -24,6 +24,7 @@ const (
 	GreetService_LongGreet_FullMethodName         = "/greet.GreetService/LongGreet"
 	GreetService_GreetEveryone_FullMethodName     = "/greet.GreetService/GreetEveryone"
 	GreetService_GreetWithDeadline_FullMethodName = "/greet.GreetService/GreetWithDeadline"
+	GreetService_Chat_FullMethodName              = "/greet.GreetService/Chat"
 )
 
 // GreetServiceClient is the client API for GreetService service.
@@ -31,10 +32,16 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GreetServiceClient interface {
 	Greet(ctx context.Context, in *GreetRequest, opts ...grpc.CallOption) (*GreetResponse, error)
+	// Streamed over HTTP as newline-delimited JSON.
 	GreetManyTimes(ctx context.Context, in *GreetRequest, opts ...grpc.CallOption) (GreetService_GreetManyTimesClient, error)
 	LongGreet(ctx context.Context, opts ...grpc.CallOption) (GreetService_LongGreetClient, error)
 	GreetEveryone(ctx context.Context, opts ...grpc.CallOption) (GreetService_GreetEveryoneClient, error)
+	// Over HTTP, the deadline is set with the Grpc-Timeout header, like "2S".
 	GreetWithDeadline(ctx context.Context, in *GreetRequest, opts ...grpc.CallOption) (*GreetResponse, error)
+	// Broadcasts what is said in a room to everyone in it, sender included,
+	// along with join and leave events. The stream leaves its room when the
+	// client closes it.
+	Chat(ctx context.Context, opts ...grpc.CallOption) (GreetService_ChatClient, error)
 }
 
 type greetServiceClient struct {
@@ -160,15 +167,52 @@ func (c *greetServiceClient) GreetWithDeadline(ctx context.Context, in *GreetReq
 	return out, nil
 }
 
+func (c *greetServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (GreetService_ChatClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GreetService_ServiceDesc.Streams[3], GreetService_Chat_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &greetServiceChatClient{stream}
+	return x, nil
+}
+
+type GreetService_ChatClient interface {
+	Send(*ChatRequest) error
+	Recv() (*ChatEvent, error)
+	grpc.ClientStream
+}
+
+type greetServiceChatClient struct {
+	grpc.ClientStream
+}
+
+func (x *greetServiceChatClient) Send(m *ChatRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *greetServiceChatClient) Recv() (*ChatEvent, error) {
+	m := new(ChatEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // GreetServiceServer is the server API for GreetService service.
 // All implementations must embed UnimplementedGreetServiceServer
 // for forward compatibility
 type GreetServiceServer interface {
 	Greet(context.Context, *GreetRequest) (*GreetResponse, error)
+	// Streamed over HTTP as newline-delimited JSON.
 	GreetManyTimes(*GreetRequest, GreetService_GreetManyTimesServer) error
 	LongGreet(GreetService_LongGreetServer) error
 	GreetEveryone(GreetService_GreetEveryoneServer) error
+	// Over HTTP, the deadline is set with the Grpc-Timeout header, like "2S".
 	GreetWithDeadline(context.Context, *GreetRequest) (*GreetResponse, error)
+	// Broadcasts what is said in a room to everyone in it, sender included,
+	// along with join and leave events. The stream leaves its room when the
+	// client closes it.
+	Chat(GreetService_ChatServer) error
 	mustEmbedUnimplementedGreetServiceServer()
 }
 
@@ -191,6 +235,9 @@ func (UnimplementedGreetServiceServer) GreetEveryone(GreetService_GreetEveryoneS
 func (UnimplementedGreetServiceServer) GreetWithDeadline(context.Context, *GreetRequest) (*GreetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GreetWithDeadline not implemented")
 }
+func (UnimplementedGreetServiceServer) Chat(GreetService_ChatServer) error {
+	return status.Errorf(codes.Unimplemented, "method Chat not implemented")
+}
 func (UnimplementedGreetServiceServer) mustEmbedUnimplementedGreetServiceServer() {}
 
 // UnsafeGreetServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -313,6 +360,32 @@ func _GreetService_GreetWithDeadline_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GreetService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GreetServiceServer).Chat(&greetServiceChatServer{stream})
+}
+
+type GreetService_ChatServer interface {
+	Send(*ChatEvent) error
+	Recv() (*ChatRequest, error)
+	grpc.ServerStream
+}
+
+type greetServiceChatServer struct {
+	grpc.ServerStream
+}
+
+func (x *greetServiceChatServer) Send(m *ChatEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *greetServiceChatServer) Recv() (*ChatRequest, error) {
+	m := new(ChatRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // GreetService_ServiceDesc is the grpc.ServiceDesc for GreetService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -346,6 +419,12 @@ var GreetService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Chat",
+			Handler:       _GreetService_Chat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "greet.proto",
 }
@@ -0,0 +1,9 @@
+package proto
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v2 description of the HTTP gateway, generated
+// from the google.api.http annotations of greet.proto.
+//
+//go:embed greet.swagger.json
+var OpenAPI []byte
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mxngocqb/Golang/gRPC/auth"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+)
+
+// scopeGreetEveryone is needed to open GreetEveryone streams.
+const scopeGreetEveryone = "greet:everyone"
+
+// greetPolicy lets any authenticated caller use the greet service, except
+// for GreetEveryone which needs its own scope. Health checks and
+// reflection stay public so that probes and tools work without a token.
+var greetPolicy = auth.Policy{
+	"/" + pb.GreetService_ServiceDesc.ServiceName + "/*":             {},
+	"/" + pb.GreetService_ServiceDesc.ServiceName + "/GreetEveryone": {Scopes: []string{scopeGreetEveryone}},
+	"/grpc.health.v1.Health/*":                                       {Public: true},
+	"/grpc.reflection.v1.ServerReflection/*":                         {Public: true},
+	"/grpc.reflection.v1alpha.ServerReflection/*":                    {Public: true},
+}
+
+// newAuthenticator builds the authenticator described by cfg. It returns
+// nil when authentication is disabled, that is when neither a JWT secret
+// nor an API key file is configured.
+func newAuthenticator(cfg config) (auth.Authenticator, error) {
+	var chain auth.Any
+
+	if cfg.jwtSecretFile != "" {
+		secret, err := os.ReadFile(cfg.jwtSecretFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading JWT secret: %w", err)
+		}
+
+		a, err := auth.NewJWT([]byte(strings.TrimSpace(string(secret))), cfg.jwtIssuer)
+
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, a)
+	}
+
+	if cfg.apiKeysFile != "" {
+		a, err := auth.LoadAPIKeys(cfg.apiKeysFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("loading API keys: %w", err)
+		}
+		chain = append(chain, a)
+	}
+
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return chain, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/auth"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	secret := []byte("s3cret")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "jwt.key"), secret, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api.keys"), []byte("key-alice alice "+scopeGreetEveryone+"\nkey-bob bob\n"), 0o600))
+
+	cfg, err := parseConfig([]string{
+		"-tls=false",
+		"-jwt-secret", filepath.Join(dir, "jwt.key"),
+		"-api-keys", filepath.Join(dir, "api.keys"),
+	})
+	require.NoError(t, err)
+
+	s, _, err := newServer(cfg, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	dial := func(opts ...grpc.DialOption) *grpc.ClientConn {
+		opts = append(opts,
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		conn, err := grpc.Dial("bufnet", opts...)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	withToken := func(token string) *grpc.ClientConn {
+		return dial(grpc.WithPerRPCCredentials(auth.TokenCredentials{Token: token, Insecure: true}))
+	}
+
+	jwt, err := auth.SignJWT(secret, "", auth.Identity{Subject: "ngoc"}, time.Minute)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	greet := func(conn *grpc.ClientConn) error {
+		_, err := pb.NewGreetServiceClient(conn).Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+		return err
+	}
+	greetEveryone := func(conn *grpc.ClientConn) error {
+		stream, err := pb.NewGreetServiceClient(conn).GreetEveryone(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: "Ngoc"}))
+		_, err = stream.Recv()
+		return err
+	}
+
+	anonymous := dial()
+	assert.Equal(t, codes.Unauthenticated, status.Code(greet(anonymous)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(greet(withToken("wrong"))))
+	assert.NoError(t, greet(withToken(jwt)))
+	assert.NoError(t, greet(withToken("key-bob")))
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(greetEveryone(anonymous)))
+	assert.Equal(t, codes.PermissionDenied, status.Code(greetEveryone(withToken(jwt))))
+	assert.Equal(t, codes.PermissionDenied, status.Code(greetEveryone(withToken("key-bob"))))
+	assert.NoError(t, greetEveryone(withToken("key-alice")))
+
+	// Health checks need no token.
+	_, err = healthpb.NewHealthClient(anonymous).Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// slowPolicy is what happens to a chat client whose buffer is full.
+type slowPolicy string
+
+const (
+	// dropSlow drops the events the client has no room for.
+	dropSlow slowPolicy = "drop"
+	// disconnectSlow ends the stream of the client with ResourceExhausted.
+	disconnectSlow slowPolicy = "disconnect"
+)
+
+func (p *slowPolicy) Set(s string) error {
+	switch slowPolicy(s) {
+	case dropSlow, disconnectSlow:
+		*p = slowPolicy(s)
+		return nil
+	}
+	return fmt.Errorf("want %s or %s", dropSlow, disconnectSlow)
+}
+
+func (p *slowPolicy) String() string {
+	return string(*p)
+}
+
+const (
+	defaultChatBuffer = 64
+	maxChatField      = 64
+	maxChatText       = 4096
+)
+
+// chatHub fans the events of each room out to the clients in it.
+type chatHub struct {
+	buffer int
+	policy slowPolicy
+
+	mu    sync.Mutex
+	rooms map[string]map[*chatClient]struct{}
+}
+
+// chatClient is a Chat stream. Events wait in a buffer of bounded size
+// until they are sent.
+type chatClient struct {
+	events chan *pb.ChatEvent
+	// slow is closed when the client is disconnected for being too slow.
+	slow chan struct{}
+
+	// Guarded by the hub mutex.
+	room    string
+	name    string
+	dropped int
+}
+
+func newChatHub(buffer int, policy slowPolicy) *chatHub {
+	if buffer <= 0 {
+		buffer = defaultChatBuffer
+	}
+	if policy == "" {
+		policy = dropSlow
+	}
+	return &chatHub{buffer: buffer, policy: policy, rooms: map[string]map[*chatClient]struct{}{}}
+}
+
+func (h *chatHub) connect() *chatClient {
+	return &chatClient{
+		events: make(chan *pb.ChatEvent, h.buffer),
+		slow:   make(chan struct{}),
+	}
+}
+
+// join moves c to room under name, telling both rooms.
+func (h *chatHub) join(c *chatClient, room, name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leaveLocked(c)
+
+	if h.rooms[room] == nil {
+		h.rooms[room] = map[*chatClient]struct{}{}
+	}
+	h.rooms[room][c] = struct{}{}
+	c.room, c.name = room, name
+
+	h.broadcastLocked(room, &pb.ChatEvent{Type: pb.ChatEvent_TYPE_JOINED, Room: room, Name: name})
+}
+
+// leave removes c from its room, if any, logging how many events it
+// missed for being too slow.
+func (h *chatHub) leave(c *chatClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leaveLocked(c)
+
+	if c.dropped > 0 {
+		log.Printf("Chat client %q left after missing %d events\n", c.name, c.dropped)
+	}
+}
+
+func (h *chatHub) leaveLocked(c *chatClient) {
+	members, ok := h.rooms[c.room]
+	if !ok {
+		return
+	}
+	if _, ok := members[c]; !ok {
+		return
+	}
+
+	delete(members, c)
+	if len(members) == 0 {
+		delete(h.rooms, c.room)
+	}
+	h.broadcastLocked(c.room, &pb.ChatEvent{Type: pb.ChatEvent_TYPE_LEFT, Room: c.room, Name: c.name})
+}
+
+// say sends text from c to everyone in its room, c included.
+func (h *chatHub) say(c *chatClient, text string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.rooms[c.room][c]; !ok {
+		return status.Error(codes.FailedPrecondition, "join a room first")
+	}
+
+	h.broadcastLocked(c.room, &pb.ChatEvent{Type: pb.ChatEvent_TYPE_MESSAGE, Room: c.room, Name: c.name, Text: text})
+	return nil
+}
+
+// broadcastLocked queues ev for every client in room, applying the slow
+// policy to those whose buffer is full. h.mu must be held.
+func (h *chatHub) broadcastLocked(room string, ev *pb.ChatEvent) {
+	ev.Time = timestamppb.Now()
+
+	var slow []*chatClient
+	for c := range h.rooms[room] {
+		select {
+		case c.events <- ev:
+		default:
+			if h.policy == dropSlow {
+				c.dropped++
+				continue
+			}
+			slow = append(slow, c)
+		}
+	}
+
+	for _, c := range slow {
+		select {
+		case <-c.slow:
+			// Already disconnected by a nested broadcast.
+		default:
+			close(c.slow)
+			h.leaveLocked(c)
+		}
+	}
+}
+
+// Chat relays the requests of the stream to the hub and the events of its
+// room back to the stream.
+func (s *Server) Chat(stream pb.GreetService_ChatServer) error {
+	ctx := stream.Context()
+	c := s.chat.connect()
+	defer s.chat.leave(c)
+
+	recvErr := make(chan error, 1)
+	go func() {
+		recvErr <- s.chatRecv(stream, c)
+	}()
+
+	for {
+		select {
+		case ev := <-c.events:
+			if err := stream.Send(ev); err != nil {
+				return streamError(ctx, "sending chat event", err)
+			}
+		case <-c.slow:
+			return status.Error(codes.ResourceExhausted, "too slow to receive the chat events")
+		case err := <-recvErr:
+			if err != nil {
+				return err
+			}
+			return flushChat(stream, c)
+		case <-ctx.Done():
+			return contextError(ctx)
+		}
+	}
+}
+
+// flushChat sends the events already queued for c, among which the echo
+// of the last message of a client that just closed its side.
+func flushChat(stream pb.GreetService_ChatServer, c *chatClient) error {
+	for {
+		select {
+		case ev := <-c.events:
+			if err := stream.Send(ev); err != nil {
+				return streamError(stream.Context(), "sending chat event", err)
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// chatRecv handles the requests of the stream until the client closes it,
+// which returns nil, or until an error.
+func (s *Server) chatRecv(stream pb.GreetService_ChatServer, c *chatClient) error {
+	for {
+		req, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return streamError(stream.Context(), "reading chat stream", err)
+		}
+
+		switch action := req.Action.(type) {
+		case *pb.ChatRequest_Join:
+			room, name := strings.TrimSpace(action.Join.Room), strings.TrimSpace(action.Join.Name)
+			if err := validateChatJoin(room, name); err != nil {
+				return err
+			}
+			s.chat.join(c, room, name)
+		case *pb.ChatRequest_Text:
+			if utf8.RuneCountInString(action.Text) > maxChatText {
+				return status.Errorf(codes.InvalidArgument, "text must be at most %d characters", maxChatText)
+			}
+			if err := s.chat.say(c, action.Text); err != nil {
+				return err
+			}
+		default:
+			return status.Error(codes.InvalidArgument, "empty chat request")
+		}
+	}
+}
+
+func validateChatJoin(room, name string) error {
+	for field, v := range map[string]string{"room": room, "name": name} {
+		if v == "" || utf8.RuneCountInString(v) > maxChatField {
+			return status.Errorf(codes.InvalidArgument, "%s must be 1 to %d characters", field, maxChatField)
+		}
+	}
+	return nil
+}
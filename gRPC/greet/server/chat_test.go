@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func joinRequest(room, name string) *pb.ChatRequest {
+	return &pb.ChatRequest{Action: &pb.ChatRequest_Join{Join: &pb.JoinRoom{Room: room, Name: name}}}
+}
+
+func textRequest(text string) *pb.ChatRequest {
+	return &pb.ChatRequest{Action: &pb.ChatRequest_Text{Text: text}}
+}
+
+func TestChatFansOutToEveryClient(t *testing.T) {
+	c, _ := startServer(t)
+	const clients = 20
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Everyone joins before anyone speaks.
+	streams := make([]pb.GreetService_ChatClient, clients)
+	for i := range streams {
+		stream, err := c.Chat(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(joinRequest("lobby", fmt.Sprint("user-", i))))
+
+		ev, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, pb.ChatEvent_TYPE_JOINED, ev.Type)
+		streams[i] = stream
+	}
+
+	// A separate room hears nothing of the lobby.
+	other, err := c.Chat(ctx)
+	require.NoError(t, err)
+	require.NoError(t, other.Send(joinRequest("other", "lurker")))
+
+	var wg sync.WaitGroup
+	for i, stream := range streams {
+		wg.Add(1)
+		go func(i int, stream pb.GreetService_ChatClient) {
+			defer wg.Done()
+			assert.NoError(t, stream.Send(textRequest(fmt.Sprint("hi from ", i))))
+
+			// Skip the join events of the clients who joined later.
+			got := map[string]bool{}
+			for len(got) < clients {
+				ev, err := stream.Recv()
+				if !assert.NoError(t, err) {
+					return
+				}
+				if ev.Type == pb.ChatEvent_TYPE_MESSAGE {
+					assert.Equal(t, "lobby", ev.Room)
+					got[ev.Text] = true
+				}
+			}
+			for j := 0; j < clients; j++ {
+				assert.True(t, got[fmt.Sprint("hi from ", j)], "client %d missed message %d", i, j)
+			}
+		}(i, stream)
+	}
+	wg.Wait()
+
+	ev, err := other.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, &pb.ChatEvent{Type: pb.ChatEvent_TYPE_JOINED, Room: "other", Name: "lurker"}, &pb.ChatEvent{Type: ev.Type, Room: ev.Room, Name: ev.Name})
+
+	// Closing a stream leaves the room.
+	require.NoError(t, streams[0].CloseSend())
+	_, err = streams[0].Recv()
+	assert.Equal(t, io.EOF, err)
+
+	for {
+		ev, err := streams[1].Recv()
+		require.NoError(t, err)
+		if ev.Type == pb.ChatEvent_TYPE_LEFT {
+			assert.Equal(t, "user-0", ev.Name)
+			break
+		}
+	}
+}
+
+func TestChatDeliversQueuedEventsOnClose(t *testing.T) {
+	c, _ := startServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := c.Chat(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(joinRequest("lobby", "ngoc")))
+	require.NoError(t, stream.Send(textRequest("bye")))
+	require.NoError(t, stream.CloseSend())
+
+	var got []pb.ChatEvent_Type
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, ev.Type)
+	}
+	assert.Equal(t, []pb.ChatEvent_Type{pb.ChatEvent_TYPE_JOINED, pb.ChatEvent_TYPE_MESSAGE}, got)
+}
+
+func TestChatRejectsInvalidRequests(t *testing.T) {
+	c, _ := startServer(t)
+	ctx := context.Background()
+
+	for name, req := range map[string]*pb.ChatRequest{
+		"text before join": textRequest("hello"),
+		"empty room":       joinRequest(" ", "ngoc"),
+		"empty request":    {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			stream, err := c.Chat(ctx)
+			require.NoError(t, err)
+			require.NoError(t, stream.Send(req))
+
+			_, err = stream.Recv()
+			code := status.Code(err)
+			assert.Contains(t, []codes.Code{codes.InvalidArgument, codes.FailedPrecondition}, code)
+		})
+	}
+}
+
+func TestChatHubSlowClients(t *testing.T) {
+	t.Run("drop", func(t *testing.T) {
+		h := newChatHub(2, dropSlow)
+		slow, fast := h.connect(), h.connect()
+		h.join(slow, "room", "slow")
+		h.join(fast, "room", "fast")
+
+		<-fast.events
+		for i := 0; i < 3; i++ {
+			require.NoError(t, h.say(fast, fmt.Sprint(i)))
+			<-fast.events
+		}
+
+		// The slow client kept its first 2 events and is still in the room.
+		assert.Len(t, slow.events, 2)
+		h.mu.Lock()
+		assert.Equal(t, 3, slow.dropped)
+		assert.Contains(t, h.rooms["room"], slow)
+		h.mu.Unlock()
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		h := newChatHub(2, disconnectSlow)
+		slow, fast := h.connect(), h.connect()
+		h.join(slow, "room", "slow")
+		h.join(fast, "room", "fast")
+
+		// The 2 join events filled the buffer of the slow client.
+		<-fast.events
+		require.NoError(t, h.say(fast, "overflows it"))
+		<-fast.events
+
+		select {
+		case <-slow.slow:
+		default:
+			t.Fatal("the slow client was not disconnected")
+		}
+
+		ev := <-fast.events
+		assert.Equal(t, pb.ChatEvent_TYPE_LEFT, ev.Type)
+		assert.Equal(t, "slow", ev.Name)
+		assert.Error(t, h.say(slow, "too late"))
+	})
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mxngocqb/Golang/gRPC/compression"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// payloadCounter adds up the sizes of the messages a client receives,
+// before and after decompression.
+type payloadCounter struct {
+	wire, data atomic.Int64
+}
+
+func (p *payloadCounter) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (p *payloadCounter) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InPayload); ok {
+		p.wire.Add(int64(in.CompressedLength))
+		p.data.Add(int64(in.Length))
+	}
+}
+
+func (p *payloadCounter) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (p *payloadCounter) HandleConn(context.Context, stats.ConnStats) {}
+
+// startCompressed serves newServer(cfg) over an in-memory listener, and
+// returns a client compressing its requests with compressor.
+func startCompressed(tb testing.TB, cfg config, compressor string) (pb.GreetServiceClient, *payloadCounter) {
+	tb.Helper()
+
+	cfg.streamLimits = defaultStreamLimits
+	s, _, err := newServer(cfg, prometheus.NewRegistry())
+	require.NoError(tb, err)
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	tb.Cleanup(s.Stop)
+
+	counter := &payloadCounter{}
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(counter),
+		grpc.WithDefaultCallOptions(compression.CallOptions(compressor)...),
+	)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { conn.Close() })
+
+	return pb.NewGreetServiceClient(conn), counter
+}
+
+// greetMany reads a whole GreetManyTimes stream of count responses
+// carrying payloadSize bytes each.
+func greetMany(c pb.GreetServiceClient, count, payloadSize uint32) error {
+	stream, err := c.GreetManyTimes(context.Background(), &pb.GreetRequest{
+		FirstName:   "Ngoc",
+		Count:       count,
+		Interval:    durationpb.New(0),
+		PayloadSize: payloadSize,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		_, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func TestResponseCompression(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		server     string
+		client     string
+		compressed bool
+	}{
+		{"uncompressed", "", compression.Identity, false},
+		{"like the request gzip", "", compression.Gzip, true},
+		{"like the request zstd", "", compression.Zstd, true},
+		{"server prefers zstd", compression.Zstd, compression.Identity, true},
+		{"server prefers identity", compression.Identity, compression.Gzip, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, counter := startCompressed(t, config{compression: tc.server}, tc.client)
+			require.NoError(t, greetMany(c, 3, 64<<10))
+
+			if tc.compressed {
+				assert.Less(t, counter.wire.Load(), counter.data.Load()/10)
+			} else {
+				assert.Equal(t, counter.data.Load(), counter.wire.Load())
+			}
+		})
+	}
+}
+
+func TestMessageSizeLimits(t *testing.T) {
+	c, _ := startCompressed(t, config{maxRecvSize: 1 << 10, maxSendSize: 1 << 10}, compression.Identity)
+	long := strings.Repeat("x", 2<<10)
+
+	_, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: long})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	stream, err := c.LongGreet(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: long}))
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "larger than max")
+
+	err = greetMany(c, 1, 2<<10)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "larger than max")
+
+	// The send limit applies to compressed messages.
+	gzipped, _ := startCompressed(t, config{maxSendSize: 1 << 10}, compression.Gzip)
+	assert.NoError(t, greetMany(gzipped, 1, 2<<10))
+}
+
+// BenchmarkGreetManyTimes compares the time and the bytes received, once
+// compressed and before decompression, of GreetManyTimes streams with each
+// compressor. Both ends run in the benchmark, so ns/op covers compressing
+// and decompressing.
+func BenchmarkGreetManyTimes(b *testing.B) {
+	for _, compressor := range compression.Names {
+		for _, size := range []uint32{1 << 10, 64 << 10} {
+			b.Run(fmt.Sprintf("%s/%dKiB", compressor, size>>10), func(b *testing.B) {
+				c, counter := startCompressed(b, config{}, compressor)
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					if err := greetMany(c, 10, size); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(counter.wire.Load())/float64(b.N), "wire-B/op")
+				b.ReportMetric(float64(counter.data.Load())/float64(b.N), "data-B/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/compression"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/ratelimit"
+	"github.com/mxngocqb/Golang/gRPC/tracing"
+)
+
+type config struct {
+	addr     string
+	tls      bool
+	mtls     bool
+	certFile string
+	keyFile  string
+	caFile   string
+
+	certReload time.Duration
+
+	reflection   bool
+	drainTimeout time.Duration
+	metricsAddr  string
+	httpAddr     string
+
+	jwtSecretFile string
+	jwtIssuer     string
+	apiKeysFile   string
+
+	limits ratelimit.Limits
+
+	chatBuffer int
+	chatPolicy slowPolicy
+
+	streamLimits streamLimits
+
+	compression string
+	maxRecvSize int
+	maxSendSize int
+
+	tracing tracing.Config
+}
+
+// defaultLimits keep a single client from exhausting the server, in
+// particular with GreetManyTimes streams which last 10 seconds.
+const defaultLimits = "* rate=10 burst=20 streams=8; GreetManyTimes streams=2"
+
+// defaultMaxMessageSize is the default of grpc for received messages,
+// applied to sent messages as well.
+const defaultMaxMessageSize = 4 << 20
+
+// parseConfig reads the server configuration from args. Every flag
+// defaults to the value of its GREET_* environment variable, so flags
+// override the environment.
+func parseConfig(args []string) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.StringVar(&cfg.addr, "addr", envString("GREET_ADDR", "localhost:50051"), "address to listen on [GREET_ADDR]")
+	fs.BoolVar(&cfg.tls, "tls", envBool("GREET_TLS", true), "serve over TLS [GREET_TLS]")
+	fs.BoolVar(&cfg.mtls, "mtls", envBool("GREET_MTLS", false), "require client certificates signed by -ca, implies -tls [GREET_MTLS]")
+	fs.StringVar(&cfg.certFile, "cert", envString("GREET_CERT", "ssl/server.crt"), "server certificate [GREET_CERT]")
+	fs.StringVar(&cfg.keyFile, "key", envString("GREET_KEY", "ssl/server.pem"), "server private key [GREET_KEY]")
+	fs.StringVar(&cfg.caFile, "ca", envString("GREET_CA", "ssl/ca.crt"), "CA verifying client certificates in mTLS mode [GREET_CA]")
+	fs.DurationVar(&cfg.certReload, "cert-reload", envDuration("GREET_CERT_RELOAD", 10*time.Second), "how often to look for a renewed -cert and -key [GREET_CERT_RELOAD]")
+
+	fs.BoolVar(&cfg.reflection, "reflection", envBool("GREET_REFLECTION", false), "enable server reflection [GREET_REFLECTION]")
+	fs.DurationVar(&cfg.drainTimeout, "drain-timeout", envDuration("GREET_DRAIN_TIMEOUT", 15*time.Second), "time given to in-flight RPCs on shutdown [GREET_DRAIN_TIMEOUT]")
+
+	fs.StringVar(&cfg.metricsAddr, "metrics-addr", envString("GREET_METRICS_ADDR", "localhost:9090"), "address serving Prometheus metrics, empty to disable [GREET_METRICS_ADDR]")
+	fs.StringVar(&cfg.httpAddr, "http-addr", envString("GREET_HTTP_ADDR", "localhost:8080"), "address serving the HTTP/JSON gateway, empty to disable [GREET_HTTP_ADDR]")
+
+	fs.StringVar(&cfg.jwtSecretFile, "jwt-secret", envString("GREET_JWT_SECRET_FILE", ""), "file holding the HMAC secret of accepted JWTs, enables authentication [GREET_JWT_SECRET_FILE]")
+	fs.StringVar(&cfg.jwtIssuer, "jwt-issuer", envString("GREET_JWT_ISSUER", ""), "issuer required in accepted JWTs [GREET_JWT_ISSUER]")
+	fs.StringVar(&cfg.apiKeysFile, "api-keys", envString("GREET_API_KEYS_FILE", ""), "file of accepted API keys, enables authentication [GREET_API_KEYS_FILE]")
+
+	cfg.chatPolicy = dropSlow
+	if v := envString("GREET_CHAT_SLOW", ""); v != "" {
+		if err := cfg.chatPolicy.Set(v); err != nil {
+			return config{}, fmt.Errorf("invalid GREET_CHAT_SLOW: %w", err)
+		}
+	}
+	fs.IntVar(&cfg.chatBuffer, "chat-buffer", envInt("GREET_CHAT_BUFFER", defaultChatBuffer), "chat events buffered per client [GREET_CHAT_BUFFER]")
+	fs.Var(&cfg.chatPolicy, "chat-slow", "what to do with chat clients whose buffer is full: drop events or disconnect [GREET_CHAT_SLOW]")
+
+	fs.IntVar(&cfg.streamLimits.maxCount, "max-count", envInt("GREET_MAX_COUNT", defaultStreamLimits.maxCount), "largest GreetManyTimes count [GREET_MAX_COUNT]")
+	fs.DurationVar(&cfg.streamLimits.minInterval, "min-interval", envDuration("GREET_MIN_INTERVAL", defaultStreamLimits.minInterval), "shortest GreetManyTimes interval [GREET_MIN_INTERVAL]")
+	fs.IntVar(&cfg.streamLimits.maxPayloadSize, "max-payload", envInt("GREET_MAX_PAYLOAD", defaultStreamLimits.maxPayloadSize), "largest GreetManyTimes payload size in bytes [GREET_MAX_PAYLOAD]")
+	fs.DurationVar(&cfg.streamLimits.maxBackoff, "max-backoff", envDuration("GREET_MAX_BACKOFF", defaultStreamLimits.maxBackoff), "longest delay added between the responses of a stream the client does not keep up with [GREET_MAX_BACKOFF]")
+
+	fs.StringVar(&cfg.compression, "compression", envString("GREET_COMPRESSION", ""), "compressor of the responses when the client accepts it: identity, gzip or zstd, empty to compress like the request [GREET_COMPRESSION]")
+	fs.IntVar(&cfg.maxRecvSize, "max-recv-size", envInt("GREET_MAX_RECV_SIZE", defaultMaxMessageSize), "largest request message in bytes, once decompressed [GREET_MAX_RECV_SIZE]")
+	fs.IntVar(&cfg.maxSendSize, "max-send-size", envInt("GREET_MAX_SEND_SIZE", defaultMaxMessageSize), "largest response message in bytes, once compressed [GREET_MAX_SEND_SIZE]")
+
+	fs.StringVar(&cfg.tracing.Exporter, "trace", envString("GREET_TRACE", tracing.None), "where to export traces: none, stdout or otlp [GREET_TRACE]")
+	fs.StringVar(&cfg.tracing.OTLPEndpoint, "otlp-endpoint", envString("GREET_OTLP_ENDPOINT", ""), "host:port of the OTLP gRPC collector, see also OTEL_EXPORTER_OTLP_ENDPOINT [GREET_OTLP_ENDPOINT]")
+	fs.BoolVar(&cfg.tracing.OTLPInsecure, "otlp-insecure", envBool("GREET_OTLP_INSECURE", false), "connect to the OTLP collector without TLS [GREET_OTLP_INSECURE]")
+
+	limits := fs.String("limits", envString("GREET_LIMITS", defaultLimits), "per client limits, like \""+defaultLimits+"\", \"none\" to disable [GREET_LIMITS]")
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	if *limits != "none" {
+		var err error
+		cfg.limits, err = ratelimit.ParseLimits(*limits, pb.GreetService_ServiceDesc.ServiceName)
+
+		if err != nil {
+			return config{}, fmt.Errorf("invalid -limits: %w", err)
+		}
+	}
+
+	if err := compression.Check(cfg.compression); err != nil {
+		return config{}, fmt.Errorf("invalid -compression: %w", err)
+	}
+
+	if cfg.mtls {
+		cfg.tls = true
+	}
+	return cfg, nil
+}
+
+func envString(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func envBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// contextError returns the status matching why ctx is done, or nil if it
+// is not done yet.
+func contextError(ctx context.Context) error {
+	switch ctx.Err() {
+	case context.Canceled:
+		return status.Error(codes.Canceled, "The client canceled the request")
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, "The deadline was exceeded")
+	}
+	return nil
+}
+
+// streamError converts an error returned by Send or Recv into a status the
+// client can act upon: Canceled or DeadlineExceeded when the call is over,
+// Unavailable when the transport went away and Internal otherwise.
+func streamError(ctx context.Context, op string, err error) error {
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded:
+		return err
+	case codes.Unavailable:
+		return status.Errorf(codes.Unavailable, "Error while %s: %v", op, err)
+	}
+
+	if errors.Is(err, io.EOF) {
+		return status.Errorf(codes.Unavailable, "Error while %s: stream closed", op)
+	}
+	return status.Errorf(codes.Internal, "Error while %s: %v", op, err)
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return contextError(ctx)
+	case <-t.C:
+		return nil
+	}
+}
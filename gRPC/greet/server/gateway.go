@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/mxngocqb/Golang/gRPC/auth"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// gatewayNetwork is the network of the in-memory connection between the
+// HTTP gateway and the gRPC server.
+const gatewayNetwork = "bufconn"
+
+// newGateway serves s on an in-memory listener and returns the HTTP/JSON
+// handler proxying to it, along with the OpenAPI description at
+// /openapi.json. The returned function closes the connection to s. The
+// gateway dials s without TLS, so s must be built without credentials, like
+// the local server of newServers.
+func newGateway(ctx context.Context, s *grpc.Server) (http.Handler, func(), error) {
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+
+	conn, err := grpc.DialContext(ctx, "passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting the gateway: %w", err)
+	}
+
+	// Leave out the empty fields, like the payload only load tests ask for.
+	gw := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{
+				MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: false},
+				UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+			},
+		}),
+		runtime.WithIncomingHeaderMatcher(gatewayHeader),
+	)
+
+	if err := pb.RegisterGreetServiceHandler(ctx, gw, conn); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("registering the gateway: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", gw)
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(pb.OpenAPI)
+	})
+
+	return mux, func() { conn.Close() }, nil
+}
+
+// gatewayHeader passes the W3C trace context and baggage headers of HTTP
+// requests on under their own names, so that the gRPC calls continue the
+// trace of the HTTP client. Other headers get the default treatment.
+func gatewayHeader(key string) (string, bool) {
+	switch k := strings.ToLower(key); k {
+	case "traceparent", "tracestate", "baggage":
+		return k, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// serveGateway serves h on lis until ctx is done, over TLS when tlsCfg is
+// set. In-flight requests then get drainTimeout to finish, and the calls
+// they leave on local, the server behind the gateway, are drained within
+// what remains of it. The returned channel is closed once both stopped.
+func serveGateway(ctx context.Context, lis net.Listener, h http.Handler, local *grpc.Server, tlsCfg *tls.Config, drainTimeout time.Duration) <-chan struct{} {
+	srv := &http.Server{Handler: h, TLSConfig: tlsCfg, ReadHeaderTimeout: 5 * time.Second}
+
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("Serving the HTTP gateway on %s (TLS: %v)\n", lis.Addr(), tlsCfg != nil)
+
+		if tlsCfg != nil {
+			errc <- srv.ServeTLS(lis, "", "")
+		} else {
+			errc <- srv.Serve(lis)
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		select {
+		case err := <-errc:
+			log.Printf("Failed to serve the HTTP gateway: %v\n", err)
+			local.Stop()
+			return
+		case <-ctx.Done():
+		}
+
+		deadline := time.Now().Add(drainTimeout)
+		shutdownCtx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Println("Drain timeout exceeded, closing the remaining HTTP requests")
+			srv.Close()
+		}
+		<-errc
+
+		stopWithin(local, time.Until(deadline))
+	}()
+
+	return done
+}
+
+// limiterKey identifies the callers of the rate limiter. The calls proxied
+// by the gateway all come from the same in-memory connection, so anonymous
+// HTTP clients are told apart by the last address in the x-forwarded-for
+// metadata the gateway sets, which it takes from the HTTP connection.
+func limiterKey(ctx context.Context) string {
+	if _, ok := auth.FromContext(ctx); ok {
+		return ratelimit.ClientKey(ctx)
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr.Network() != gatewayNetwork {
+		return ratelimit.ClientKey(ctx)
+	}
+
+	forwarded := metadata.ValueFromIncomingContext(ctx, "x-forwarded-for")
+	if len(forwarded) == 0 {
+		return ratelimit.ClientKey(ctx)
+	}
+
+	hops := strings.Split(forwarded[len(forwarded)-1], ",")
+	return "peer:" + strings.TrimSpace(hops[len(hops)-1])
+}
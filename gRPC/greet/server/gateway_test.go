@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func startGateway(t *testing.T) *httptest.Server {
+	t.Helper()
+	return startGatewayWith(t, config{})
+}
+
+// startGatewayWith serves the gateway of the servers built from cfg.
+func startGatewayWith(t *testing.T, cfg config) *httptest.Server {
+	t.Helper()
+
+	s, local, _, err := newServers(cfg, prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(s.Stop)
+	t.Cleanup(local.Stop)
+
+	h, closeGateway, err := newGateway(context.Background(), local)
+	require.NoError(t, err)
+	t.Cleanup(closeGateway)
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func decodeBody(t *testing.T, res *http.Response) map[string]interface{} {
+	t.Helper()
+	defer res.Body.Close()
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	return body
+}
+
+func TestGatewayGreet(t *testing.T) {
+	srv := startGateway(t)
+
+	res, err := http.Get(srv.URL + "/v1/greet?first_name=Ngoc")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, map[string]interface{}{"result": "Hello Ngoc"}, decodeBody(t, res))
+
+	res, err = http.Post(srv.URL+"/v1/greet", "application/json", strings.NewReader(`{"firstName": "Clement"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, map[string]interface{}{"result": "Hello Clement"}, decodeBody(t, res))
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/greet?first_name=Clement", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Language", "fr")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"result": "Salut Clement"}, decodeBody(t, res))
+
+	res, err = http.Get(srv.URL + "/v1/greet")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Contains(t, decodeBody(t, res)["details"], map[string]interface{}{
+		"@type": "type.googleapis.com/google.rpc.BadRequest",
+		"fieldViolations": []interface{}{
+			map[string]interface{}{"field": "first_name", "description": "must not be empty"},
+		},
+	})
+
+	res, err = http.Post(srv.URL+"/v1/greet", "application/json", strings.NewReader(`{`))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+// TestGatewayGreetWithTLS checks that the gateway still reaches the greet
+// service when the gRPC server only accepts TLS connections.
+func TestGatewayGreetWithTLS(t *testing.T) {
+	dir := t.TempDir()
+	writeTestCerts(t, dir)
+
+	cfg, err := parseConfig([]string{
+		"-cert", filepath.Join(dir, "server.crt"),
+		"-key", filepath.Join(dir, "server.pem"),
+	})
+	require.NoError(t, err)
+	require.True(t, cfg.tls)
+
+	srv := startGatewayWith(t, cfg)
+
+	res, err := http.Get(srv.URL + "/v1/greet?first_name=Ngoc")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, map[string]interface{}{"result": "Hello Ngoc"}, decodeBody(t, res))
+}
+
+func TestGatewayGreetWithDeadline(t *testing.T) {
+	srv := startGateway(t)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/greet/deadline?first_name=Ngoc", nil)
+	require.NoError(t, err)
+	req.Header.Set("Grpc-Timeout", "500m")
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusGatewayTimeout, res.StatusCode)
+}
+
+func TestGatewayGreetManyTimesStreamsJSONLines(t *testing.T) {
+	srv := startGateway(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/greet/many?first_name=Ngoc", nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	lines := bufio.NewScanner(res.Body)
+	for i, want := range []string{"Hello Ngoc, number 0", "Hello Ngoc, number 1"} {
+		require.True(t, lines.Scan(), "line %d", i)
+
+		var msg struct {
+			Result struct {
+				Result string `json:"result"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(lines.Bytes(), &msg))
+		assert.Equal(t, want, msg.Result.Result)
+	}
+}
+
+func TestGatewayServesOpenAPI(t *testing.T) {
+	srv := startGateway(t)
+
+	res, err := http.Get(srv.URL + "/openapi.json")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), `"/v1/greet/many"`)
+}
+
+func TestLimiterKey(t *testing.T) {
+	gateway := peer.NewContext(context.Background(), &peer.Peer{Addr: fakeAddr{gatewayNetwork, "bufconn"}})
+	direct := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1000}})
+	forwarded := metadata.Pairs("x-forwarded-for", "1.2.3.4, 10.0.0.2")
+
+	assert.Equal(t, "peer:10.0.0.2", limiterKey(metadata.NewIncomingContext(gateway, forwarded)))
+	assert.Equal(t, "peer:bufconn", limiterKey(gateway))
+	assert.Equal(t, "peer:10.0.0.1", limiterKey(metadata.NewIncomingContext(direct, forwarded)))
+}
+
+type fakeAddr struct {
+	network, addr string
+}
+
+func (a fakeAddr) Network() string { return a.network }
+func (a fakeAddr) String() string  { return a.addr }
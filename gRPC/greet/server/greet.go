@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
-	"log"
 
 	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
 )
 
 func (*Server) Greet(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
-	log.Printf("Greet was invoked with %v\n", in)
-	return &pb.GreetResponse{Result: "Hello " + in.FirstName}, nil
-}
\ No newline at end of file
+	if err := validateRequest(in); err != nil {
+		return nil, err
+	}
+
+	return &pb.GreetResponse{Result: greeting(ctx, in)}, nil
+}
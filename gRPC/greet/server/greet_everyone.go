@@ -2,13 +2,12 @@ package main
 
 import (
 	"io"
-	"log"
 
 	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
 )
 
 func (s *Server) GreetEveryone(stream pb.GreetService_GreetEveryoneServer) error {
-	log.Println("GreetEveryone was invoked")
+	ctx := stream.Context()
 
 	for {
 		req, err := stream.Recv()
@@ -18,15 +17,20 @@ func (s *Server) GreetEveryone(stream pb.GreetService_GreetEveryoneServer) error
 		}
 
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v\n", err)
+			return streamError(ctx, "reading client stream", err)
 		}
-		res := "Hello " + req.FirstName + "!"
+
+		if err := validateRequest(req); err != nil {
+			return err
+		}
+
+		res := greeting(ctx, req) + "!"
 		err = stream.Send(&pb.GreetResponse{
 			Result: res,
 		})
 
 		if err != nil {
-			log.Fatalf("Error while sending data to client: %v\n", err)
+			return streamError(ctx, "sending data to client", err)
 		}
 	}
 }
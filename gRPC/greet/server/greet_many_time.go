@@ -1,22 +1,142 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
-	"log"
 	"time"
+
 	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
 )
 
+// streamLimits are the maximums the server enforces on GreetManyTimes
+// requests.
+type streamLimits struct {
+	maxCount       int
+	minInterval    time.Duration
+	maxPayloadSize int
+	// maxBackoff bounds the delay added between responses when the
+	// client does not keep up.
+	maxBackoff time.Duration
+}
+
+var defaultStreamLimits = streamLimits{
+	maxCount:       10000,
+	minInterval:    0,
+	maxPayloadSize: 1 << 20,
+	maxBackoff:     5 * time.Second,
+}
+
+const (
+	defaultCount    = 10
+	defaultInterval = time.Second
+)
+
+func (l streamLimits) check(in *pb.GreetRequest, v *violations) {
+	if int64(in.Count) > int64(l.maxCount) {
+		v.add("count", fmt.Sprintf("must be at most %d", l.maxCount))
+	}
+
+	if in.Interval != nil {
+		if err := in.Interval.CheckValid(); err != nil {
+			v.add("interval", "must be a valid duration")
+		} else if d := in.Interval.AsDuration(); d < l.minInterval || d < 0 {
+			v.add("interval", fmt.Sprintf("must be at least %v", l.minInterval))
+		}
+	}
+
+	if int64(in.PayloadSize) > int64(l.maxPayloadSize) {
+		v.add("payload_size", fmt.Sprintf("must be at most %d", l.maxPayloadSize))
+	}
+}
+
 func (s *Server) GreetManyTimes(in *pb.GreetRequest, stream pb.GreetService_GreetManyTimesServer) error {
-	log.Printf("GreetManyTimes function was invoked with: %v\n", in)
+	var v violations
+	checkRequest(in, &v)
+	s.streamLimits.check(in, &v)
+
+	count := in.Count
+	if count == 0 {
+		count = defaultCount
+	}
+	if in.ResumeFrom >= count {
+		v.add("resume_from", fmt.Sprintf("must be below count (%d)", count))
+	}
+
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	interval := defaultInterval
+	if in.Interval != nil {
+		interval = in.Interval.AsDuration()
+	}
+
+	ctx := stream.Context()
+	greet := greeting(ctx, in)
+	payload := bytes.Repeat([]byte{'x'}, int(in.PayloadSize))
+	pace := newPacer(interval, s.streamLimits.maxBackoff)
+
+	for i := in.ResumeFrom; i < count; i++ {
+		if i > in.ResumeFrom {
+			if err := sleep(ctx, pace.delay()); err != nil {
+				return err
+			}
+		}
 
-	for i := 0; i < 10; i++ {
-		res := fmt.Sprintf("Hello %s, number %d", in.FirstName, i)
-		stream.Send(&pb.GreetResponse{
-			Result: res,
+		res := fmt.Sprintf("%s, number %d", greet, i)
+		start := time.Now()
+		err := stream.Send(&pb.GreetResponse{
+			Result:   res,
+			Payload:  payload,
+			Sequence: i,
 		})
-		time.Sleep(time.Second)
+
+		if err != nil {
+			return streamError(ctx, "sending data to client", err)
+		}
+		pace.sent(time.Since(start))
 	}
 
 	return nil
 }
+
+// slowSend is how long a Send has to block to tell that the client reads
+// slower than the server writes. Send blocks once the HTTP/2 flow control
+// window of the stream is full.
+const slowSend = 50 * time.Millisecond
+
+// pacer spaces the responses of a stream by the requested interval, and
+// backs off exponentially while the client is not keeping up, so that a
+// slow reader does not keep the server busy filling its window.
+type pacer struct {
+	interval   time.Duration
+	maxBackoff time.Duration
+	backoff    time.Duration
+}
+
+func newPacer(interval, maxBackoff time.Duration) *pacer {
+	return &pacer{interval: interval, maxBackoff: maxBackoff}
+}
+
+// sent records how long the last Send blocked.
+func (p *pacer) sent(took time.Duration) {
+	switch {
+	case took < slowSend:
+		if p.backoff /= 2; p.backoff < slowSend {
+			p.backoff = 0
+		}
+	case p.backoff == 0:
+		p.backoff = slowSend
+	default:
+		p.backoff *= 2
+	}
+
+	if p.backoff > p.maxBackoff {
+		p.backoff = p.maxBackoff
+	}
+}
+
+// delay is the time to wait before the next Send.
+func (p *pacer) delay() time.Duration {
+	return p.interval + p.backoff
+}
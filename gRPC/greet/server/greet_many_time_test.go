@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestGreetManyTimesCountIntervalPayload(t *testing.T) {
+	c, _ := startServer(t)
+
+	start := time.Now()
+	stream, err := c.GreetManyTimes(context.Background(), &pb.GreetRequest{
+		FirstName:   "Ngoc",
+		Count:       5,
+		Interval:    durationpb.New(20 * time.Millisecond),
+		PayloadSize: 1000,
+	})
+	require.NoError(t, err)
+
+	var n int
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Len(t, res.Payload, 1000)
+		n++
+	}
+	assert.Equal(t, 5, n)
+	assert.GreaterOrEqual(t, time.Since(start), 4*20*time.Millisecond)
+}
+
+func TestGreetManyTimesResumeFrom(t *testing.T) {
+	c, _ := startServer(t)
+
+	stream, err := c.GreetManyTimes(context.Background(), &pb.GreetRequest{
+		FirstName:  "Ngoc",
+		Count:      5,
+		Interval:   durationpb.New(0),
+		ResumeFrom: 3,
+	})
+	require.NoError(t, err)
+
+	var sequence []uint32
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Contains(t, res.Result, fmt.Sprintf("number %d", res.Sequence))
+		sequence = append(sequence, res.Sequence)
+	}
+	assert.Equal(t, []uint32{3, 4}, sequence)
+
+	stream, err = c.GreetManyTimes(context.Background(), &pb.GreetRequest{
+		FirstName:  "Ngoc",
+		Count:      5,
+		ResumeFrom: 5,
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, "resume_from", st.Details()[0].(*errdetails.BadRequest).FieldViolations[0].Field)
+}
+
+func TestGreetManyTimesLimits(t *testing.T) {
+	c, _ := startServer(t)
+
+	stream, err := c.GreetManyTimes(context.Background(), &pb.GreetRequest{
+		FirstName:   "Ngoc",
+		Count:       uint32(defaultStreamLimits.maxCount + 1),
+		Interval:    durationpb.New(-time.Second),
+		PayloadSize: uint32(defaultStreamLimits.maxPayloadSize + 1),
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	var fields []string
+	for _, v := range st.Details()[0].(*errdetails.BadRequest).FieldViolations {
+		fields = append(fields, v.Field)
+	}
+	assert.ElementsMatch(t, []string{"count", "interval", "payload_size"}, fields)
+}
+
+func TestPacerBacksOffSlowReaders(t *testing.T) {
+	p := newPacer(10*time.Millisecond, 200*time.Millisecond)
+
+	p.sent(time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, p.delay())
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		p.sent(time.Second)
+		delays = append(delays, p.delay()-10*time.Millisecond)
+	}
+	assert.Equal(t, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond, 200 * time.Millisecond}, delays)
+
+	// Once the client catches up, the backoff fades away.
+	for i := 0; i < 10; i++ {
+		p.sent(time.Millisecond)
+	}
+	assert.Equal(t, 10*time.Millisecond, p.delay())
+}
@@ -2,26 +2,23 @@ package main
 
 import (
 	"context"
-	"log"
 	"time"
 
 	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (c *Server) GreetWithDeadline(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
-	log.Printf("GreetWithDeadline was invoked with %v\n", in)
+	if err := validateRequest(in); err != nil {
+		return nil, err
+	}
 
 	for i := 0; i < 3; i++ {
-		if ctx.Err() == context.DeadlineExceeded {
-			log.Println("The client cancled the request!")
-			return nil, status.Error(codes.Canceled, "The client canceled the request")
+		if err := sleep(ctx, time.Second); err != nil {
+			return nil, err
 		}
-		time.Sleep(time.Second)
 	}
 
 	return &pb.GreetResponse{
-		Result: "Hello" + in.FirstName,
+		Result: greeting(ctx, in),
 	}, nil
 }
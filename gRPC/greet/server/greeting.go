@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/metadata"
+)
+
+// phrases are the greetings of a language, formatted with the name of
+// the person greeted.
+type phrases struct {
+	casual string
+	formal string
+	// familyFirst languages put the last name first in formal greetings.
+	familyFirst bool
+}
+
+// greetings are the supported languages, English first as it is the
+// fallback.
+var greetings = []struct {
+	tag language.Tag
+	phrases
+}{
+	{language.English, phrases{casual: "Hello %s", formal: "Good day, %s"}},
+	{language.French, phrases{casual: "Salut %s", formal: "Bonjour %s"}},
+	{language.Spanish, phrases{casual: "Hola %s", formal: "Buenos días, %s"}},
+	{language.German, phrases{casual: "Hallo %s", formal: "Guten Tag, %s"}},
+	{language.Vietnamese, phrases{casual: "Chào %s", formal: "Xin chào %s", familyFirst: true}},
+	{language.Japanese, phrases{casual: "こんにちは、%s", formal: "こんにちは、%s様", familyFirst: true}},
+}
+
+var matcher = func() language.Matcher {
+	tags := make([]language.Tag, len(greetings))
+	for i, g := range greetings {
+		tags[i] = g.tag
+	}
+	return language.NewMatcher(tags)
+}()
+
+// acceptLanguageKeys are where the preferred languages of the caller are
+// looked up when the request has no locale. The HTTP gateway forwards the
+// Accept-Language header with its grpcgateway- prefix.
+var acceptLanguageKeys = []string{"accept-language", "grpcgateway-accept-language"}
+
+// greeting returns the greeting for in, in the language of its locale or
+// of the caller's accept-language metadata. The locale is expected to have
+// been checked by validateRequest.
+func greeting(ctx context.Context, in *pb.GreetRequest) string {
+	p := localePhrases(ctx, in.Locale)
+
+	if in.Style == pb.Style_STYLE_FORMAL {
+		return fmt.Sprintf(p.formal, fullName(in, p.familyFirst))
+	}
+	return fmt.Sprintf(p.casual, in.FirstName)
+}
+
+func localePhrases(ctx context.Context, locale string) phrases {
+	var prefs []language.Tag
+
+	if locale != "" {
+		if tag, err := language.Parse(locale); err == nil {
+			prefs = append(prefs, tag)
+		}
+	} else {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, key := range acceptLanguageKeys {
+			for _, v := range md.Get(key) {
+				tags, _, _ := language.ParseAcceptLanguage(v)
+				prefs = append(prefs, tags...)
+			}
+		}
+	}
+
+	_, i, confidence := matcher.Match(prefs...)
+	if confidence == language.No {
+		i = 0
+	}
+	return greetings[i].phrases
+}
+
+func fullName(in *pb.GreetRequest, familyFirst bool) string {
+	if in.LastName == "" {
+		return in.FirstName
+	}
+	if familyFirst {
+		return in.LastName + " " + in.FirstName
+	}
+	return in.FirstName + " " + in.LastName
+}
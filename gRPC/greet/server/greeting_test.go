@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGreetLocalized(t *testing.T) {
+	c, _ := startServer(t)
+
+	for _, tt := range []struct {
+		name           string
+		req            *pb.GreetRequest
+		acceptLanguage string
+		want           string
+	}{
+		{"default", &pb.GreetRequest{FirstName: "Ngoc"}, "", "Hello Ngoc"},
+		{"formal", &pb.GreetRequest{FirstName: "Ngoc", LastName: "Nguyen", Style: pb.Style_STYLE_FORMAL}, "", "Good day, Ngoc Nguyen"},
+		{"locale", &pb.GreetRequest{FirstName: "Clement", Locale: "fr-CA"}, "", "Salut Clement"},
+		{"family name first", &pb.GreetRequest{FirstName: "Ngoc", LastName: "Nguyen", Locale: "vi", Style: pb.Style_STYLE_FORMAL}, "", "Xin chào Nguyen Ngoc"},
+		{"accept-language", &pb.GreetRequest{FirstName: "Ana"}, "it, es;q=0.8, en;q=0.5", "Hola Ana"},
+		{"locale wins", &pb.GreetRequest{FirstName: "Ana", Locale: "de"}, "es", "Hallo Ana"},
+		{"unsupported", &pb.GreetRequest{FirstName: "Ana", Locale: "sw"}, "", "Hello Ana"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.acceptLanguage != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", tt.acceptLanguage)
+			}
+
+			res, err := c.Greet(ctx, tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res.Result)
+		})
+	}
+}
+
+// TestGreetRequestWireCompatible checks that requests from clients built
+// before last_name, locale and style existed are still greeted the same.
+func TestGreetRequestWireCompatible(t *testing.T) {
+	c, _ := startServer(t)
+
+	// Field 1, first_name, is all the old clients send.
+	old := append([]byte{0x0a, 0x04}, "Ngoc"...)
+
+	var req pb.GreetRequest
+	require.NoError(t, proto.Unmarshal(old, &req))
+
+	res, err := c.Greet(context.Background(), &req)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ngoc", res.Result)
+}
+
+func TestGreetInvalidArgument(t *testing.T) {
+	c, _ := startServer(t)
+	ctx := context.Background()
+
+	fieldViolations := func(err error) map[string]string {
+		t.Helper()
+
+		st := status.Convert(err)
+		require.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 1)
+		br, ok := st.Details()[0].(*errdetails.BadRequest)
+		require.True(t, ok)
+
+		fields := map[string]string{}
+		for _, v := range br.FieldViolations {
+			fields[v.Field] = v.Description
+		}
+		return fields
+	}
+
+	_, err := c.Greet(ctx, &pb.GreetRequest{FirstName: "  "})
+	assert.Equal(t, map[string]string{"first_name": "must not be empty"}, fieldViolations(err))
+
+	long := strings.Repeat("é", maxNameLength+1)
+	_, err = c.GreetWithDeadline(ctx, &pb.GreetRequest{FirstName: "Ngoc", LastName: long, Locale: "not a locale", Style: 42})
+	assert.Equal(t, map[string]string{
+		"last_name": "must be at most 100 characters",
+		"locale":    "must be a BCP 47 language tag",
+		"style":     "unknown style",
+	}, fieldViolations(err))
+
+	stream, err := c.GreetManyTimes(ctx, &pb.GreetRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Contains(t, fieldViolations(err), "first_name")
+
+	everyone, err := c.GreetEveryone(ctx)
+	require.NoError(t, err)
+	require.NoError(t, everyone.Send(&pb.GreetRequest{FirstName: "Ngoc"}))
+	_, err = everyone.Recv()
+	require.NoError(t, err)
+	require.NoError(t, everyone.Send(&pb.GreetRequest{}))
+	_, err = everyone.Recv()
+	assert.Contains(t, fieldViolations(err), "first_name")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// servedServices are the services whose health is reported, "" being the
+// status of the server as a whole.
+var servedServices = []string{
+	"",
+	pb.GreetService_ServiceDesc.ServiceName,
+}
+
+// registerHealth adds the grpc.health.v1 service to s and reports every
+// served service as SERVING.
+func registerHealth(s *grpc.Server) *health.Server {
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+
+	for _, service := range servedServices {
+		hs.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+	return hs
+}
@@ -3,14 +3,11 @@ package main
 import (
 	"fmt"
 	"io"
-	"log"
 
 	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
 )
 
 func (s *Server) LongGreet(strem pb.GreetService_LongGreetServer) error {
-	log.Printf("LongGreet function was invoked")
-
 	res := ""
 
 	for {
@@ -23,10 +20,13 @@ func (s *Server) LongGreet(strem pb.GreetService_LongGreetServer) error {
 		}
 
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v\n", err)
+			return streamError(strem.Context(), "reading client stream", err)
+		}
+
+		if err := validateRequest(req); err != nil {
+			return err
 		}
 
-		res += fmt.Sprintf("Hello %s!\n", req.FirstName)
-		
+		res += fmt.Sprintf("%s!\n", greeting(strem.Context(), req))
 	}
 }
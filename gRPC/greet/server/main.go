@@ -1,51 +1,221 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"log"
+	"log/slog"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/mxngocqb/Golang/gRPC/auth"
+	"github.com/mxngocqb/Golang/gRPC/compression"
 	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/interceptor"
+	"github.com/mxngocqb/Golang/gRPC/ratelimit"
+	"github.com/mxngocqb/Golang/gRPC/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 )
 
-var addr string = "localhost:50051"
-
 type Server struct {
 	pb.GreetServiceServer
+	chat         *chatHub
+	streamLimits streamLimits
 }
 
-func main() {
-	lis, err := net.Listen("tcp", addr)
+// keepalivePolicy lets clients ping every 20 seconds, idle or not, which
+// the greet client relies on to detect dead connections.
+var keepalivePolicy = keepalive.EnforcementPolicy{
+	MinTime:             20 * time.Second,
+	PermitWithoutStream: true,
+}
+
+// newServer builds the gRPC server described by cfg with every service
+// registered and the interceptor chain installed, recording RPC metrics in
+// reg and tracing calls with the global tracer provider. The health server
+// is returned so that the caller can report the server as NOT_SERVING when
+// it shuts down.
+func newServer(cfg config, reg prometheus.Registerer) (*grpc.Server, *health.Server, error) {
+	s, _, hs, err := newServers(cfg, reg)
+	return s, hs, err
+}
+
+// newServers builds the server of newServer along with a local server for
+// the HTTP gateway. The local server shares the greet service and the
+// interceptor chain of the first, but never uses TLS since it is only
+// served over an in-memory connection.
+func newServers(cfg config, reg prometheus.Registerer) (s, local *grpc.Server, hs *health.Server, err error) {
+	logger := slog.Default()
+	metrics := interceptor.NewMetrics(reg)
+
+	// Logging and metrics come first so that they see the Internal status
+	// a recovered panic turns into, as well as rejected calls.
+	unary := []grpc.UnaryServerInterceptor{
+		interceptor.UnaryLogging(logger),
+		metrics.Unary(),
+		interceptor.UnaryRecovery(logger),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		interceptor.StreamLogging(logger),
+		metrics.Stream(),
+		interceptor.StreamRecovery(logger),
+	}
+
+	// Handlers read the subject of the client certificate with
+	// interceptor.Subject.
+	if cfg.mtls {
+		unary = append(unary, interceptor.UnarySubject())
+		stream = append(stream, interceptor.StreamSubject())
+	}
+
+	authenticator, err := newAuthenticator(cfg)
 
 	if err != nil {
-		log.Fatalf("Failed to listen on: %v\n", err)
+		return nil, nil, nil, err
 	}
 
-	defer lis.Close()
+	if authenticator != nil {
+		unary = append(unary, auth.UnaryServerInterceptor(authenticator, greetPolicy))
+		stream = append(stream, auth.StreamServerInterceptor(authenticator, greetPolicy))
+	}
+
+	// The compressor of the responses is chosen per call, among those
+	// the client accepts.
+	if cfg.compression != "" {
+		unary = append(unary, compression.UnaryServerInterceptor(cfg.compression))
+		stream = append(stream, compression.StreamServerInterceptor(cfg.compression))
+	}
 
-	log.Printf("Listening on %s\n", addr)
+	// Limits come after authentication to count calls per identity.
+	if len(cfg.limits) > 0 {
+		limiter := ratelimit.New(cfg.limits)
+		limiter.Key = limiterKey
+		unary = append(unary, limiter.Unary())
+		stream = append(stream, limiter.Stream())
+	}
 
-	opts := []grpc.ServerOption{}
-	tls := true // change that to false if need
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(tracing.ServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+		grpc.KeepaliveEnforcementPolicy(keepalivePolicy),
+	}
 
-	if tls {
-		certFile := "ssl/server.crt"
-		keyFile := "ssl/server.pem"
-		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+	// Messages over the limits fail with ResourceExhausted, zero keeps the
+	// defaults of grpc.
+	if cfg.maxRecvSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.maxRecvSize))
+	}
+	if cfg.maxSendSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.maxSendSize))
+	}
+
+	greet := &Server{
+		chat:         newChatHub(cfg.chatBuffer, cfg.chatPolicy),
+		streamLimits: cfg.streamLimits,
+	}
+
+	local = grpc.NewServer(opts...)
+	pb.RegisterGreetServiceServer(local, greet)
+
+	if cfg.tls {
+		creds, err := serverCredentials(cfg)
 
 		if err != nil {
-			log.Fatalf("Failed loading certficates: %v\n", err)
+			return nil, nil, nil, err
 		}
 		opts = append(opts, grpc.Creds(creds))
 	}
 
-	s := grpc.NewServer(opts...)
-	pb.RegisterGreetServiceServer(s, &Server{})
+	s = grpc.NewServer(opts...)
+	pb.RegisterGreetServiceServer(s, greet)
+	hs = registerHealth(s)
 
-	if err = s.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve: %v\n", err)
+	if cfg.reflection {
+		reflection.Register(s)
 	}
+
+	return s, local, hs, nil
 }
 
+func main() {
+	cfg, err := parseConfig(os.Args[1:])
+
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v\n", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), "greet-server", cfg.tracing)
+
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v\n", err)
+	}
+
+	defer shutdownTracing(context.Background())
+
+	reg := newRegistry()
+	s, local, hs, err := newServers(cfg, reg)
+
+	if err != nil {
+		log.Fatalf("Failed to set up the server: %v\n", err)
+	}
+
+	lis, err := net.Listen("tcp", cfg.addr)
+
+	if err != nil {
+		log.Fatalf("Failed to listen on: %v\n", err)
+	}
+
+	defer lis.Close()
+
+	log.Printf("Listening on %s (TLS: %v, mTLS: %v, reflection: %v)\n", cfg.addr, cfg.tls, cfg.mtls, cfg.reflection)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.metricsAddr != "" {
+		go serveMetrics(ctx, cfg.metricsAddr, reg)
+	}
+
+	var gatewayDone <-chan struct{}
+	if cfg.httpAddr != "" {
+		h, closeGateway, err := newGateway(ctx, local)
+
+		if err != nil {
+			log.Fatalf("Failed to set up the HTTP gateway: %v\n", err)
+		}
+		defer closeGateway()
+
+		var tlsCfg *tls.Config
+		if cfg.tls {
+			tlsCfg, err = serverTLSConfig(cfg)
 
+			if err != nil {
+				log.Fatalf("Failed to set up the HTTP gateway: %v\n", err)
+			}
+		}
+
+		httpLis, err := net.Listen("tcp", cfg.httpAddr)
+
+		if err != nil {
+			log.Fatalf("Failed to listen on: %v\n", err)
+		}
+		gatewayDone = serveGateway(ctx, httpLis, h, local, tlsCfg, cfg.drainTimeout)
+	}
+
+	if err = serve(ctx, s, hs, lis, cfg.drainTimeout); err != nil {
+		log.Fatalf("Failed to serve: %v\n", err)
+	}
+
+	// The gateway drains alongside the gRPC server.
+	if gatewayDone != nil {
+		<-gatewayDone
+	}
+}
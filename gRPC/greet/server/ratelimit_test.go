@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestRateLimits(t *testing.T) {
+	cfg, err := parseConfig([]string{"-tls=false", "-limits", "Greet rate=1 burst=2; GreetManyTimes streams=1"})
+	require.NoError(t, err)
+
+	s, _, err := newServer(cfg, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	c := pb.NewGreetServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+		require.NoError(t, err)
+	}
+
+	var header metadata.MD
+	_, err = c.Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"}, grpc.Header(&header))
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, []string{"1"}, header.Get(ratelimit.RetryAfterKey))
+	require.Len(t, st.Details(), 1)
+	assert.IsType(t, &errdetails.RetryInfo{}, st.Details()[0])
+
+	first, err := c.GreetManyTimes(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	_, err = first.Recv()
+	require.NoError(t, err)
+
+	second, err := c.GreetManyTimes(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	_, err = second.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	header, err = second.Header()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, header.Get(ratelimit.RetryAfterKey))
+
+	// Other streams are not capped.
+	everyone, err := c.GreetEveryone(ctx)
+	require.NoError(t, err)
+	require.NoError(t, everyone.Send(&pb.GreetRequest{FirstName: "Ngoc"}))
+	_, err = everyone.Recv()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/greet/greettest"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// handlerResult is what a stream handler returned, as seen by the server.
+type handlerResult struct {
+	method string
+	err    error
+	took   time.Duration
+}
+
+// startServer serves the greet service over an in-memory listener and
+// reports the outcome of every handler on the returned channel.
+func startServer(t *testing.T, opts ...greettest.Option) (pb.GreetServiceClient, <-chan handlerResult) {
+	t.Helper()
+
+	results := make(chan handlerResult, 10)
+	report := func(method string, start time.Time, err error) {
+		results <- handlerResult{method: method, err: err, took: time.Since(start)}
+	}
+
+	opts = append(opts, greettest.WithServerOptions(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			start := time.Now()
+			res, err := handler(ctx, req)
+			report(info.FullMethod, start, err)
+			return res, err
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			start := time.Now()
+			err := handler(srv, ss)
+			report(info.FullMethod, start, err)
+			return err
+		}),
+	))
+
+	c, _ := greettest.Start(t, func(s *grpc.Server) {
+		pb.RegisterGreetServiceServer(s, &Server{chat: newChatHub(0, dropSlow), streamLimits: defaultStreamLimits})
+	}, opts...)
+	return c, results
+}
+
+func waitResult(t *testing.T, results <-chan handlerResult) handlerResult {
+	t.Helper()
+
+	select {
+	case r := <-results:
+		return r
+	case <-time.After(3 * time.Second):
+		t.Fatal("the handler did not return")
+		return handlerResult{}
+	}
+}
+
+func assertStillServing(t *testing.T, c pb.GreetServiceClient) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := c.Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ngoc", res.Result)
+}
+
+func TestGreetManyTimesStopsWhenClientCancels(t *testing.T) {
+	c, results := startServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.GreetManyTimes(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ngoc, number 0", res.Result)
+	cancel()
+
+	r := waitResult(t, results)
+	assert.Equal(t, codes.Canceled, status.Code(r.err))
+	assert.Less(t, r.took, 2*time.Second)
+
+	assertStillServing(t, c)
+}
+
+func TestLongGreetAbortedMidStream(t *testing.T) {
+	c, results := startServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.LongGreet(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: "Clement"}))
+	cancel()
+
+	r := waitResult(t, results)
+	assert.Equal(t, codes.Canceled, status.Code(r.err))
+
+	assertStillServing(t, c)
+}
+
+func TestLongGreet(t *testing.T) {
+	c, _ := startServer(t)
+
+	stream, err := c.LongGreet(context.Background())
+	require.NoError(t, err)
+	for _, name := range []string{"Clement", "Ngoc"} {
+		require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: name}))
+	}
+
+	res, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Clement!\nHello Ngoc!\n", res.Result)
+}
+
+func TestGreetEveryoneAbortedMidStream(t *testing.T) {
+	c, results := startServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.GreetEveryone(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: "Clement"}))
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Clement!", res.Result)
+	cancel()
+
+	r := waitResult(t, results)
+	assert.Equal(t, codes.Canceled, status.Code(r.err))
+
+	assertStillServing(t, c)
+}
+
+func TestGreetEveryone(t *testing.T) {
+	c, _ := startServer(t)
+
+	stream, err := c.GreetEveryone(context.Background())
+	require.NoError(t, err)
+
+	for _, name := range []string{"Clement", "Ngoc"} {
+		require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: name}))
+		res, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "Hello "+name+"!", res.Result)
+	}
+	require.NoError(t, stream.CloseSend())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestGreet(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []greettest.Option
+	}{
+		{"insecure", nil},
+		{"TLS", []greettest.Option{greettest.WithTLS()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := startServer(t, tc.opts...)
+			assertStillServing(t, c)
+
+			_, err := c.Greet(context.Background(), &pb.GreetRequest{})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestGreetManyTimes(t *testing.T) {
+	c, results := startServer(t)
+
+	stream, err := c.GreetManyTimes(context.Background(), &pb.GreetRequest{
+		FirstName: "Ngoc",
+		Count:     3,
+		Interval:  durationpb.New(time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	for i := uint32(0); i < 3; i++ {
+		res, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Hello Ngoc, number %d", i), res.Result)
+		assert.Equal(t, i, res.Sequence)
+	}
+
+	// The end of the stream reads as EOF, every time.
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	r := waitResult(t, results)
+	assert.NoError(t, r.err)
+}
+
+func TestGreetWithDeadline(t *testing.T) {
+	c, results := startServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := c.GreetWithDeadline(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ngoc", res.Result)
+
+	r := waitResult(t, results)
+	assert.NoError(t, r.err)
+}
+
+func TestGreetWithDeadlineExpires(t *testing.T) {
+	c, results := startServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GreetWithDeadline(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// The handler returns as soon as the call is over, whether the server
+	// sees the deadline pass or the client cancel the stream first.
+	r := waitResult(t, results)
+	assert.Equal(t, "/greet.GreetService/GreetWithDeadline", r.method)
+	assert.Error(t, r.err)
+	assert.Less(t, r.took, 2*time.Second)
+
+	assertStillServing(t, c)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// serve runs s on lis until ctx is done or Serve fails. On shutdown every
+// service is reported NOT_SERVING and in-flight RPCs get drainTimeout to
+// finish before the remaining ones are closed, which their clients see as
+// Unavailable.
+func serve(ctx context.Context, s *grpc.Server, hs *health.Server, lis net.Listener, drainTimeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.Serve(lis)
+	}()
+
+	select {
+	case err := <-errc:
+		hs.Shutdown()
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, draining in-flight RPCs for up to %v\n", drainTimeout)
+	hs.Shutdown()
+
+	if stopWithin(s, drainTimeout) {
+		log.Println("All RPCs finished")
+	} else {
+		log.Println("Drain timeout exceeded, closing the remaining RPCs")
+	}
+
+	return <-errc
+}
+
+// stopWithin stops s gracefully, closing the RPCs still running after
+// timeout. It reports whether they all finished in time.
+func stopWithin(s *grpc.Server, timeout time.Duration) bool {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+		return true
+	case <-timer.C:
+		s.Stop()
+		<-stopped
+		return false
+	}
+}
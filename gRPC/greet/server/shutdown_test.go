@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startServing runs the greet server through serve and returns a connection
+// to it, a function triggering the shutdown and a channel receiving what
+// serve returned.
+func startServing(t *testing.T, drainTimeout time.Duration) (*grpc.ClientConn, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	s, hs, err := newServer(config{}, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	ctx, shutdown := context.WithCancel(context.Background())
+	t.Cleanup(shutdown)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, s, hs, lis, drainTimeout)
+	}()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, shutdown, done
+}
+
+func waitServe(t *testing.T, done <-chan error, within time.Duration) {
+	t.Helper()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(within):
+		t.Fatal("serve did not return")
+	}
+}
+
+func TestShutdownLetsActiveStreamsFinish(t *testing.T) {
+	conn, shutdown, done := startServing(t, 5*time.Second)
+	c := pb.NewGreetServiceClient(conn)
+
+	stream, err := c.GreetEveryone(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: "Clement"}))
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	shutdown()
+
+	// The stream keeps working while the server drains.
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: "Ngoc"}))
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ngoc!", res.Result)
+
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	waitServe(t, done, time.Second)
+}
+
+func TestShutdownClosesStreamsAfterDrainTimeout(t *testing.T) {
+	conn, shutdown, done := startServing(t, 300*time.Millisecond)
+	c := pb.NewGreetServiceClient(conn)
+
+	watch, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{
+		Service: pb.GreetService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	health, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.Status)
+
+	stream, err := c.GreetManyTimes(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	start := time.Now()
+	shutdown()
+
+	health, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, health.Status)
+
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	waitServe(t, done, time.Second)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+// startGatewayServing runs the HTTP gateway through serveGateway and
+// returns its URL, a function triggering the shutdown and a channel closed
+// once the gateway stopped.
+func startGatewayServing(t *testing.T, drainTimeout time.Duration) (string, context.CancelFunc, <-chan struct{}) {
+	t.Helper()
+
+	s, local, _, err := newServers(config{streamLimits: defaultStreamLimits}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(s.Stop)
+
+	h, closeGateway, err := newGateway(context.Background(), local)
+	require.NoError(t, err)
+	t.Cleanup(closeGateway)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	ctx, shutdown := context.WithCancel(context.Background())
+	t.Cleanup(shutdown)
+
+	return "http://" + lis.Addr().String(), shutdown, serveGateway(ctx, lis, h, local, nil, drainTimeout)
+}
+
+func waitGateway(t *testing.T, done <-chan struct{}, within time.Duration) {
+	t.Helper()
+
+	select {
+	case <-done:
+	case <-time.After(within):
+		t.Fatal("the gateway did not stop")
+	}
+}
+
+func TestGatewayShutdownLetsActiveStreamsFinish(t *testing.T) {
+	url, shutdown, done := startGatewayServing(t, 5*time.Second)
+
+	res, err := http.Get(url + "/v1/greet/many?first_name=Ngoc&count=4&interval=0.2s")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	lines := bufio.NewScanner(res.Body)
+	require.True(t, lines.Scan())
+
+	shutdown()
+
+	// The stream keeps going while the gateway drains.
+	n := 1
+	for lines.Scan() {
+		n++
+	}
+	assert.NoError(t, lines.Err())
+	assert.Equal(t, 4, n)
+
+	waitGateway(t, done, time.Second)
+}
+
+func TestGatewayShutdownClosesStreamsAfterDrainTimeout(t *testing.T) {
+	url, shutdown, done := startGatewayServing(t, 300*time.Millisecond)
+
+	res, err := http.Get(url + "/v1/greet/many?first_name=Ngoc&count=100&interval=0.2s")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	lines := bufio.NewScanner(res.Body)
+	require.True(t, lines.Scan())
+
+	start := time.Now()
+	shutdown()
+
+	n := 1
+	for lines.Scan() {
+		n++
+	}
+	assert.Less(t, n, 100)
+
+	waitGateway(t, done, time.Second)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/mxngocqb/Golang/gRPC/certs"
+	"google.golang.org/grpc/credentials"
+)
+
+// serverCredentials builds the TLS credentials described by cfg.
+func serverCredentials(cfg config) (credentials.TransportCredentials, error) {
+	tlsCfg, err := serverTLSConfig(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// serverTLSConfig builds the TLS configuration shared by the gRPC server
+// and the HTTP gateway. The certificate is reloaded when its files change.
+// In mTLS mode clients must present a certificate signed by cfg.caFile.
+func serverTLSConfig(cfg config) (*tls.Config, error) {
+	keyPair, err := certs.LoadKeyPair(cfg.certFile, cfg.keyFile, cfg.certReload)
+
+	if err != nil {
+		return nil, fmt.Errorf("loading server certificate: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		GetCertificate: keyPair.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	if cfg.mtls {
+		pool, err := loadCertPool(cfg.caFile)
+
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsCfg, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("loading CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", caFile)
+	}
+	return pool, nil
+}
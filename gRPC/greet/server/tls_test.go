@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/certs"
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/interceptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// writeTestCerts writes a CA plus server and client certificates signed by
+// it into dir, named like the files produced by certgen. The CA is
+// returned to issue more.
+func writeTestCerts(t *testing.T, dir string) *certs.Authority {
+	t.Helper()
+
+	ca, err := certs.NewAuthority("ca", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, ca.WriteFiles(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")))
+
+	err = ca.IssueFiles(certs.Request{CommonName: "localhost", Hosts: []string{"localhost"}, Validity: time.Hour, Usage: x509.ExtKeyUsageServerAuth},
+		filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.pem"))
+	require.NoError(t, err)
+	err = ca.IssueFiles(certs.Request{CommonName: "greet-client", Validity: time.Hour, Usage: x509.ExtKeyUsageClientAuth},
+		filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.pem"))
+	require.NoError(t, err)
+	return ca
+}
+
+func TestMutualTLSExposesClientSubject(t *testing.T) {
+	dir := t.TempDir()
+	writeTestCerts(t, dir)
+
+	cfg, err := parseConfig([]string{
+		"-addr", "localhost:0",
+		"-mtls",
+		"-cert", filepath.Join(dir, "server.crt"),
+		"-key", filepath.Join(dir, "server.pem"),
+		"-ca", filepath.Join(dir, "ca.crt"),
+	})
+	require.NoError(t, err)
+	assert.True(t, cfg.tls)
+
+	creds, err := serverCredentials(cfg)
+	require.NoError(t, err)
+
+	subjects := make(chan string, 1)
+	s := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(
+		interceptor.UnarySubject(),
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			subject, _ := interceptor.Subject(ctx)
+			subjects <- subject
+			return handler(ctx, req)
+		},
+	))
+	pb.RegisterGreetServiceServer(s, &Server{})
+
+	lis, err := net.Listen("tcp", cfg.addr)
+	require.NoError(t, err)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	pool := x509.NewCertPool()
+	caPEM, err := os.ReadFile(filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+	require.True(t, pool.AppendCertsFromPEM(caPEM))
+
+	dial := func(certs ...tls.Certificate) pb.GreetServiceClient {
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:      pool,
+			ServerName:   "localhost",
+			Certificates: certs,
+		})))
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return pb.NewGreetServiceClient(conn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	clientCert, err := tls.LoadX509KeyPair(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.pem"))
+	require.NoError(t, err)
+
+	res, err := dial(clientCert).Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Ngoc", res.Result)
+	assert.Equal(t, "CN=greet-client", <-subjects)
+
+	_, err = dial().Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestServerCertificateRotation(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeTestCerts(t, dir)
+
+	cfg, err := parseConfig([]string{
+		"-addr", "localhost:0",
+		"-cert", filepath.Join(dir, "server.crt"),
+		"-key", filepath.Join(dir, "server.pem"),
+		"-cert-reload", "0s",
+	})
+	require.NoError(t, err)
+
+	creds, err := serverCredentials(cfg)
+	require.NoError(t, err)
+	s := grpc.NewServer(grpc.Creds(creds))
+	pb.RegisterGreetServiceServer(s, &Server{})
+
+	lis, err := net.Listen("tcp", cfg.addr)
+	require.NoError(t, err)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca.CertPEM()))
+
+	dial := func() pb.GreetServiceClient {
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:    pool,
+			ServerName: "localhost",
+		})))
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return pb.NewGreetServiceClient(conn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// serverSerial greets through c and returns the serial number of the
+	// certificate the server presented on that connection.
+	serverSerial := func(c pb.GreetServiceClient) string {
+		var p peer.Peer
+		_, err := c.Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"}, grpc.Peer(&p))
+		require.NoError(t, err)
+		return p.AuthInfo.(credentials.TLSInfo).State.PeerCertificates[0].SerialNumber.String()
+	}
+
+	before := dial()
+	first := serverSerial(before)
+
+	err = ca.IssueFiles(certs.Request{CommonName: "localhost", Hosts: []string{"localhost"}, Validity: time.Hour, Usage: x509.ExtKeyUsageServerAuth},
+		filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.pem"))
+	require.NoError(t, err)
+
+	// New connections get the new certificate, the open one carries on.
+	second := serverSerial(dial())
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, first, serverSerial(before))
+
+	// A broken pair keeps the last good certificate in use.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server.pem"), []byte("garbage"), 0o600))
+	assert.Equal(t, second, serverSerial(dial()))
+}
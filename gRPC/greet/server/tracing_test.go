@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/mxngocqb/Golang/gRPC/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// recordSpans installs a tracer provider keeping the ended spans in memory
+// until the end of the test. It must come before newServer.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func spanNamed(t *testing.T, exporter *tracetest.InMemoryExporter, name string, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+
+	var span tracetest.SpanStub
+	require.Eventually(t, func() bool {
+		for _, s := range exporter.GetSpans() {
+			if s.Name == name && s.SpanKind == kind {
+				span = s
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+	return span
+}
+
+func TestTraceSpansClientAndServer(t *testing.T) {
+	exporter := recordSpans(t)
+
+	s, _, err := newServer(config{}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(tracing.ClientHandler()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "doGreet")
+	_, err = pb.NewGreetServiceClient(conn).Greet(ctx, &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	root.End()
+
+	const method = "greet.GreetService/Greet"
+	client := spanNamed(t, exporter, method, trace.SpanKindClient)
+	server := spanNamed(t, exporter, method, trace.SpanKindServer)
+
+	traceID := root.SpanContext().TraceID()
+	assert.Equal(t, traceID, client.SpanContext.TraceID())
+	assert.Equal(t, traceID, server.SpanContext.TraceID())
+	assert.Equal(t, root.SpanContext().SpanID(), client.Parent.SpanID())
+	assert.Equal(t, client.SpanContext.SpanID(), server.Parent.SpanID())
+	assert.True(t, server.Parent.IsRemote())
+}
+
+func TestGatewayContinuesHTTPTraces(t *testing.T) {
+	exporter := recordSpans(t)
+	srv := startGateway(t)
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/greet?first_name=Ngoc", nil)
+	require.NoError(t, err)
+	req.Header.Set("traceparent", traceparent)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	server := spanNamed(t, exporter, "greet.GreetService/Greet", trace.SpanKindServer)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxNameLength is the longest first or last name accepted, in characters.
+const maxNameLength = 100
+
+// violations are the invalid fields of a request.
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+// err returns an InvalidArgument status listing every violation as a
+// BadRequest field violation, or nil when there is none.
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	st, err := status.New(codes.InvalidArgument, "invalid GreetRequest").
+		WithDetails(&errdetails.BadRequest{FieldViolations: v})
+
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid GreetRequest")
+	}
+	return st.Err()
+}
+
+// validateRequest returns an InvalidArgument status describing the invalid
+// fields of in, or nil when in is valid.
+func validateRequest(in *pb.GreetRequest) error {
+	var v violations
+	checkRequest(in, &v)
+	return v.err()
+}
+
+func checkRequest(in *pb.GreetRequest, v *violations) {
+	if strings.TrimSpace(in.FirstName) == "" {
+		v.add("first_name", "must not be empty")
+	}
+	if utf8.RuneCountInString(in.FirstName) > maxNameLength {
+		v.add("first_name", fmt.Sprintf("must be at most %d characters", maxNameLength))
+	}
+	if utf8.RuneCountInString(in.LastName) > maxNameLength {
+		v.add("last_name", fmt.Sprintf("must be at most %d characters", maxNameLength))
+	}
+
+	if in.Locale != "" {
+		if _, err := language.Parse(in.Locale); err != nil {
+			v.add("locale", "must be a BCP 47 language tag")
+		}
+	}
+
+	if _, ok := pb.Style_name[int32(in.Style)]; !ok {
+		v.add("style", "unknown style")
+	}
+}
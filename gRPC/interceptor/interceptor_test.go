@@ -0,0 +1,83 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// panicky panics on Check unless the service asked for is "ok".
+type panicky struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (panicky) Check(_ context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if in.Service != "ok" {
+		panic("boom")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (panicky) Watch(*healthpb.HealthCheckRequest, healthpb.Health_WatchServer) error {
+	panic("boom")
+}
+
+func TestInterceptors(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	reg := prometheus.NewRegistry()
+	metrics := NewMetrics(reg)
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryLogging(logger), metrics.Unary(), UnaryRecovery(logger)),
+		grpc.ChainStreamInterceptor(StreamLogging(logger), metrics.Stream(), StreamRecovery(logger)),
+	)
+	healthpb.RegisterHealthServer(s, panicky{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	c := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+
+	_, err = c.Check(ctx, &healthpb.HealthCheckRequest{Service: "panic"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	stream, err := c.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// The server survives the panics.
+	_, err = c.Check(ctx, &healthpb.HealthCheckRequest{Service: "ok"})
+	require.NoError(t, err)
+
+	const check = "/grpc.health.v1.Health/Check"
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.handled.WithLabelValues(check, "Internal")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.handled.WithLabelValues(check, "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.handled.WithLabelValues("/grpc.health.v1.Health/Watch", "Internal")))
+
+	assert.Contains(t, logs.String(), "panic recovered")
+	assert.Contains(t, logs.String(), "method="+check+" code=OK")
+}
@@ -0,0 +1,79 @@
+// Package interceptor holds the gRPC server interceptors shared by the
+// services of this module: request logging, panic recovery, metrics and
+// the subject of client certificates.
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryLogging logs one line per unary call with its method, peer, status
+// code and latency, and its trace ID when it is traced.
+func UnaryLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, err, time.Since(start))
+		return res, err
+	}
+}
+
+// StreamLogging logs one line per stream, once it is over, with its method,
+// peer, status code and duration.
+func StreamLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), logger, info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method string, err error, latency time.Duration) {
+	code := status.Code(err)
+	attrs := []any{
+		"method", method,
+		"code", code.String(),
+		"latency", latency,
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, "peer", p.Addr.String())
+
+		if subject, ok := peerSubject(ctx); ok {
+			attrs = append(attrs, "subject", subject)
+		}
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		attrs = append(attrs, "trace_id", sc.TraceID().String())
+	}
+
+	if err != nil {
+		attrs = append(attrs, "error", status.Convert(err).Message())
+	}
+
+	level := slog.LevelInfo
+	if serverError(code) {
+		level = slog.LevelError
+	}
+	logger.Log(ctx, level, "rpc", attrs...)
+}
+
+// serverError reports whether code means the server, rather than the
+// client, is at fault.
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
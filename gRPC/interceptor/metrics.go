@@ -0,0 +1,61 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics records how many RPCs were handled and how long they took,
+// labelled by method and status code.
+type Metrics struct {
+	handled *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+}
+
+// NewMetrics creates the RPC collectors and registers them with reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "RPCs completed on the server, by method and status code.",
+		}, []string{"grpc_method", "grpc_code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time taken by the server to complete RPCs, by method and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_method", "grpc_code"}),
+	}
+
+	reg.MustRegister(m.handled, m.latency)
+	return m
+}
+
+// Unary returns the interceptor recording unary calls.
+func (m *Metrics) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		m.observe(info.FullMethod, err, time.Since(start))
+		return res, err
+	}
+}
+
+// Stream returns the interceptor recording streams once they are over.
+func (m *Metrics) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func (m *Metrics) observe(method string, err error, took time.Duration) {
+	code := status.Code(err).String()
+	m.handled.WithLabelValues(method, code).Inc()
+	m.latency.WithLabelValues(method, code).Observe(took.Seconds())
+}
@@ -0,0 +1,69 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+type subjectKey struct{}
+
+// UnarySubject passes the subject of the verified certificate the client
+// presented on to unary handlers, which read it with Subject. Only mTLS
+// connections carry one.
+func UnarySubject() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withSubject(ctx), req)
+	}
+}
+
+// StreamSubject passes the subject of the verified certificate the client
+// presented on to stream handlers, which read it with Subject on the
+// stream context.
+func StreamSubject() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &subjectStream{ServerStream: ss, ctx: withSubject(ss.Context())})
+	}
+}
+
+// Subject returns the subject of the client certificate, set by the
+// UnarySubject and StreamSubject interceptors.
+func Subject(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok
+}
+
+func withSubject(ctx context.Context) context.Context {
+	if subject, ok := peerSubject(ctx); ok {
+		return context.WithValue(ctx, subjectKey{}, subject)
+	}
+	return ctx
+}
+
+// peerSubject returns the subject of the verified certificate of the peer
+// of ctx, if any.
+func peerSubject(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+
+	if !ok {
+		return "", false
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return info.State.VerifiedChains[0][0].Subject.String(), true
+}
+
+type subjectStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *subjectStream) Context() context.Context {
+	return s.ctx
+}
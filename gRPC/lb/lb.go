@@ -0,0 +1,41 @@
+// Package lb spreads the calls of a gRPC client across several server
+// replicas. It registers the "static" resolver, for fixed lists of
+// addresses, and the "least_outstanding" balancer.
+package lb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Target returns the dial target for addrs, a comma separated list of
+// host:port endpoints or a single target with a scheme, like
+// "dns:///greet.internal:50051". The authority is the endpoint certificates
+// are checked against.
+func Target(addrs string) (target, authority string, err error) {
+	if strings.Contains(addrs, "://") {
+		if strings.Contains(addrs, ",") {
+			return "", "", fmt.Errorf("%q: targets with a scheme cannot be combined", addrs)
+		}
+		return addrs, "", nil
+	}
+
+	endpoints := splitEndpoints(addrs)
+	switch len(endpoints) {
+	case 0:
+		return "", "", fmt.Errorf("no endpoint in %q", addrs)
+	case 1:
+		return endpoints[0], "", nil
+	}
+	return Scheme + ":///" + strings.Join(endpoints, ","), endpoints[0], nil
+}
+
+func splitEndpoints(s string) []string {
+	var endpoints []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,27 @@
+package lb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTarget(t *testing.T) {
+	for _, tt := range []struct {
+		addrs, target, authority string
+	}{
+		{"localhost:50051", "localhost:50051", ""},
+		{"dns:///greet.internal:50051", "dns:///greet.internal:50051", ""},
+		{"10.0.0.1:50051, 10.0.0.2:50051,", "static:///10.0.0.1:50051,10.0.0.2:50051", "10.0.0.1:50051"},
+	} {
+		target, authority, err := Target(tt.addrs)
+		assert.NoError(t, err, tt.addrs)
+		assert.Equal(t, tt.target, target, tt.addrs)
+		assert.Equal(t, tt.authority, authority, tt.addrs)
+	}
+
+	for _, addrs := range []string{"", " , ", "dns:///a:1,dns:///b:1"} {
+		_, _, err := Target(addrs)
+		assert.Error(t, err, addrs)
+	}
+}
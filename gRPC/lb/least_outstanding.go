@@ -0,0 +1,65 @@
+package lb
+
+import (
+	"sync/atomic"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+)
+
+// LeastOutstanding is the name of the balancer sending each call to the
+// ready replica with the fewest calls in flight, streams included. Ties go
+// round robin. Only replicas reported healthy are used when the service
+// config has a healthCheckConfig.
+const LeastOutstanding = "least_outstanding"
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(LeastOutstanding, pickerBuilder{}, base.Config{HealthCheck: true}))
+}
+
+type pickerBuilder struct{}
+
+// Build returns a picker over the ready SubConns. The counts start over
+// with every picker, which is only rebuilt when the set of ready replicas
+// changes.
+func (pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	p := &picker{}
+	for sc := range info.ReadySCs {
+		p.conns = append(p.conns, &subConn{SubConn: sc})
+	}
+	return p
+}
+
+type subConn struct {
+	balancer.SubConn
+	outstanding atomic.Int64
+}
+
+type picker struct {
+	conns []*subConn
+	next  atomic.Uint32
+}
+
+func (p *picker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	start := int(p.next.Add(1))
+
+	var best *subConn
+	for i := range p.conns {
+		c := p.conns[(start+i)%len(p.conns)]
+		if best == nil || c.outstanding.Load() < best.outstanding.Load() {
+			best = c
+		}
+	}
+
+	best.outstanding.Add(1)
+	return balancer.PickResult{
+		SubConn: best.SubConn,
+		Done: func(balancer.DoneInfo) {
+			best.outstanding.Add(-1)
+		},
+	}, nil
+}
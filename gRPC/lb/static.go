@@ -0,0 +1,45 @@
+package lb
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme is the scheme of static targets, like
+// "static:///10.0.0.1:50051,10.0.0.2:50051".
+const Scheme = "static"
+
+func init() {
+	resolver.Register(staticBuilder{})
+}
+
+type staticBuilder struct{}
+
+func (staticBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	endpoints := splitEndpoints(target.Endpoint())
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoint in %q", target.URL.String())
+	}
+
+	addrs := make([]resolver.Address, len(endpoints))
+	for i, e := range endpoints {
+		addrs[i] = resolver.Address{Addr: e}
+	}
+
+	if err := cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		return nil, err
+	}
+	return staticResolver{}, nil
+}
+
+func (staticBuilder) Scheme() string {
+	return Scheme
+}
+
+// staticResolver has nothing to do once the addresses are set.
+type staticResolver struct{}
+
+func (staticResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (staticResolver) Close() {}
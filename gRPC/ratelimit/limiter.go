@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/auth"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryAfterKey is the metadata key telling a rejected client how many
+// seconds to wait before trying again.
+const RetryAfterKey = "retry-after"
+
+// idleTimeout is how long the state of a client is kept once it stopped
+// calling.
+const idleTimeout = 10 * time.Minute
+
+// Limiter enforces Limits per client, as told apart by Key.
+type Limiter struct {
+	limits Limits
+	// Key identifies the client making a call, ClientKey by default.
+	Key func(ctx context.Context) string
+	// StreamRetryAfter is the delay suggested to clients over their
+	// stream cap.
+	StreamRetryAfter time.Duration
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	streams   map[bucketKey]int
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucketKey struct {
+	client string
+	method string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New returns a limiter enforcing limits.
+func New(limits Limits) *Limiter {
+	return &Limiter{
+		limits:           limits,
+		Key:              ClientKey,
+		StreamRetryAfter: time.Second,
+		buckets:          map[bucketKey]*bucket{},
+		streams:          map[bucketKey]int{},
+		now:              time.Now,
+	}
+}
+
+// Unary returns the interceptor applying the rate limits to unary calls.
+// It has to come after the authentication interceptor for identities to
+// be used.
+func (l *Limiter) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if wait, ok := l.allow(ctx, info.FullMethod); !ok {
+			grpc.SetHeader(ctx, retryAfter(wait))
+			return nil, exhausted(wait, "rate limit of %s exceeded", info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the interceptor applying the stream caps. It has to come
+// after the authentication interceptor for identities to be used.
+func (l *Limiter) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, ok := l.acquire(ss.Context(), info.FullMethod)
+		if !ok {
+			ss.SetHeader(retryAfter(l.StreamRetryAfter))
+			return exhausted(l.StreamRetryAfter, "too many concurrent %s streams", info.FullMethod)
+		}
+		defer release()
+
+		return handler(srv, ss)
+	}
+}
+
+// allow takes a token from the bucket of the caller, or returns how long
+// the caller has to wait for one.
+func (l *Limiter) allow(ctx context.Context, method string) (time.Duration, bool) {
+	limit, ok := l.limits.lookup(method)
+	if !ok || limit.Rate == 0 {
+		return 0, true
+	}
+
+	key := bucketKey{client: l.Key(ctx), method: method}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return delay, false
+	}
+	return 0, true
+}
+
+// acquire counts a new stream of the caller, returning the function to
+// call once it is over, unless the caller is at its cap.
+func (l *Limiter) acquire(ctx context.Context, method string) (func(), bool) {
+	limit, ok := l.limits.lookup(method)
+	if !ok || limit.Streams == 0 {
+		return func() {}, true
+	}
+
+	key := bucketKey{client: l.Key(ctx), method: method}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.streams[key] >= limit.Streams {
+		return nil, false
+	}
+	l.streams[key]++
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if l.streams[key]--; l.streams[key] == 0 {
+			delete(l.streams, key)
+		}
+	}, true
+}
+
+// sweep forgets the buckets of clients idle for long enough that their
+// bucket is full again anyway. l.mu must be held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTimeout {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// ClientKey identifies the caller by its identity when authenticated, see
+// auth.FromContext, and by its host otherwise.
+func ClientKey(ctx context.Context) string {
+	if id, ok := auth.FromContext(ctx); ok {
+		return "id:" + id.Subject
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return "peer:" + addr
+}
+
+func retryAfter(wait time.Duration) metadata.MD {
+	seconds := int(math.Ceil(wait.Seconds()))
+	return metadata.Pairs(RetryAfterKey, strconv.Itoa(seconds))
+}
+
+// exhausted builds a ResourceExhausted status carrying a RetryInfo detail.
+func exhausted(wait time.Duration, format string, args ...interface{}) error {
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf(format, args...))
+
+	withInfo, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+	if err != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
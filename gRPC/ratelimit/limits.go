@@ -0,0 +1,94 @@
+// Package ratelimit protects gRPC servers from greedy clients: unary calls
+// go through a token bucket per client and method, and the number of
+// streams a client can keep open on a method is capped.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limit bounds what a single client can do on a method. Zero values mean
+// no limit.
+type Limit struct {
+	// Rate is the sustained number of unary calls allowed per second.
+	Rate float64
+	// Burst is the number of unary calls allowed at once, at least 1
+	// when Rate is set.
+	Burst int
+	// Streams is the number of streams a client can have open at once.
+	Streams int
+}
+
+// Limits maps full method names, like "/greet.GreetService/Greet", to
+// their limit. A "/package.Service/*" entry covers every method of the
+// service that has no entry of its own. Methods covered by no entry are
+// not limited.
+type Limits map[string]Limit
+
+func (l Limits) lookup(method string) (Limit, bool) {
+	if limit, ok := l[method]; ok {
+		return limit, true
+	}
+
+	if i := strings.LastIndexByte(method, '/'); i >= 0 {
+		limit, ok := l[method[:i+1]+"*"]
+		return limit, ok
+	}
+	return Limit{}, false
+}
+
+// ParseLimits reads limits written as semicolon separated entries of a
+// method followed by its settings, like
+//
+//	GreetManyTimes streams=2; * rate=10 burst=20 streams=8
+//
+// Method names not starting with '/' are relative to service, "*" standing
+// for all of its methods.
+func ParseLimits(spec, service string) (Limits, error) {
+	limits := Limits{}
+
+	for _, entry := range strings.Split(spec, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		method := fields[0]
+		if !strings.HasPrefix(method, "/") {
+			method = "/" + service + "/" + method
+		}
+
+		var limit Limit
+		for _, setting := range fields[1:] {
+			name, value, _ := strings.Cut(setting, "=")
+
+			var err error
+			switch name {
+			case "rate":
+				limit.Rate, err = strconv.ParseFloat(value, 64)
+			case "burst":
+				limit.Burst, err = strconv.Atoi(value)
+			case "streams":
+				limit.Streams, err = strconv.Atoi(value)
+			default:
+				return nil, fmt.Errorf("%s: unknown setting %q", fields[0], name)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid %s: %w", fields[0], name, err)
+			}
+		}
+
+		if limit.Rate < 0 || limit.Burst < 0 || limit.Streams < 0 {
+			return nil, fmt.Errorf("%s: negative limit", fields[0])
+		}
+		if limit.Rate > 0 && limit.Burst == 0 {
+			limit.Burst = 1
+		}
+		limits[method] = limit
+	}
+
+	return limits, nil
+}
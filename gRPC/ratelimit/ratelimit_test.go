@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mxngocqb/Golang/gRPC/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("* rate=10 burst=20 streams=8; Slow streams=2;; /other.Service/M rate=0.5", "pkg.Service")
+	require.NoError(t, err)
+	assert.Equal(t, Limits{
+		"/pkg.Service/*":    {Rate: 10, Burst: 20, Streams: 8},
+		"/pkg.Service/Slow": {Streams: 2},
+		"/other.Service/M":  {Rate: 0.5, Burst: 1},
+	}, limits)
+
+	limit, ok := limits.lookup("/pkg.Service/Fast")
+	assert.True(t, ok)
+	assert.Equal(t, 8, limit.Streams)
+	_, ok = limits.lookup("/grpc.health.v1.Health/Check")
+	assert.False(t, ok)
+
+	for _, spec := range []string{"* speed=1", "* rate=fast", "* streams=-1"} {
+		_, err := ParseLimits(spec, "pkg.Service")
+		assert.Error(t, err, spec)
+	}
+}
+
+func peerContext(addr string) context.Context {
+	tcp, _ := net.ResolveTCPAddr("tcp", addr)
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: tcp})
+}
+
+func TestAllow(t *testing.T) {
+	l := New(Limits{"/s/M": {Rate: 1, Burst: 2}})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	a := peerContext("10.0.0.1:1000")
+	for i := 0; i < 2; i++ {
+		_, ok := l.allow(a, "/s/M")
+		assert.True(t, ok)
+	}
+
+	wait, ok := l.allow(a, "/s/M")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	// Another connection from the same host shares the bucket, another
+	// host or method does not.
+	_, ok = l.allow(peerContext("10.0.0.1:2000"), "/s/M")
+	assert.False(t, ok)
+	_, ok = l.allow(peerContext("10.0.0.2:1000"), "/s/M")
+	assert.True(t, ok)
+	_, ok = l.allow(a, "/s/Other")
+	assert.True(t, ok)
+
+	// Authenticated callers are counted by identity.
+	_, ok = l.allow(auth.NewContext(a, auth.Identity{Subject: "ngoc"}), "/s/M")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = l.allow(a, "/s/M")
+	assert.True(t, ok)
+
+	now = now.Add(idleTimeout)
+	l.allow(peerContext("10.0.0.3:1000"), "/s/M")
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestAcquire(t *testing.T) {
+	l := New(Limits{"/s/M": {Streams: 2}})
+	a := peerContext("10.0.0.1:1000")
+
+	release1, ok := l.acquire(a, "/s/M")
+	require.True(t, ok)
+	release2, ok := l.acquire(a, "/s/M")
+	require.True(t, ok)
+	_, ok = l.acquire(a, "/s/M")
+	assert.False(t, ok)
+
+	_, ok = l.acquire(peerContext("10.0.0.2:1000"), "/s/M")
+	assert.True(t, ok)
+
+	release1()
+	release3, ok := l.acquire(a, "/s/M")
+	assert.True(t, ok)
+
+	release2()
+	release3()
+	assert.Len(t, l.streams, 1)
+}
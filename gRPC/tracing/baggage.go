@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+	"google.golang.org/grpc"
+)
+
+// UnaryClientBaggage adds the members of b to the baggage of every unary
+// call, unless the call context sets them already.
+func UnaryClientBaggage(b baggage.Baggage) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withBaggage(ctx, b), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientBaggage is UnaryClientBaggage for streams.
+func StreamClientBaggage(b baggage.Baggage) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withBaggage(ctx, b), desc, cc, method, opts...)
+	}
+}
+
+func withBaggage(ctx context.Context, b baggage.Baggage) context.Context {
+	merged := baggage.FromContext(ctx)
+	for _, m := range b.Members() {
+		if merged.Member(m.Key()).Key() != "" {
+			continue
+		}
+
+		var err error
+		merged, err = merged.SetMember(m)
+
+		if err != nil {
+			return ctx
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, merged)
+}
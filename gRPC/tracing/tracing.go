@@ -0,0 +1,106 @@
+// Package tracing sets up OpenTelemetry tracing for the clients and servers
+// of this module. Trace context and baggage travel in gRPC metadata with
+// the W3C traceparent, tracestate and baggage headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/stats"
+)
+
+// Exporters.
+const (
+	None   = "none"
+	Stdout = "stdout"
+	OTLP   = "otlp"
+)
+
+// Config says where spans go.
+type Config struct {
+	// Exporter is None, Stdout or OTLP.
+	Exporter string
+	// OTLPEndpoint is the host:port of the OTLP gRPC collector. When
+	// empty, the OTEL_EXPORTER_OTLP_* environment variables apply, and
+	// localhost:4317 otherwise.
+	OTLPEndpoint string
+	OTLPInsecure bool
+}
+
+// Propagator carries the W3C trace context and baggage.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Setup installs the global tracer provider exporting the spans of service
+// as cfg says, and the global propagator. The returned function flushes
+// the spans not exported yet, it must be called before exiting.
+//
+// With the None exporter, no span is recorded but the trace context of
+// incoming calls is still passed on to outgoing ones.
+func Setup(ctx context.Context, service string, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(Propagator)
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case None, "":
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return func(context.Context) error { return nil }, nil
+	case Stdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case OTLP:
+		var opts []otlptracegrpc.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, want %s, %s or %s", cfg.Exporter, None, Stdout, OTLP)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("creating the %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(service)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// options have the handlers record an event per message, which tells
+// the messages of a stream apart in time.
+func options() []otelgrpc.Option {
+	return []otelgrpc.Option{
+		otelgrpc.WithPropagators(Propagator),
+		otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+	}
+}
+
+// ServerHandler starts a span per call, child of the caller's span, with
+// the global tracer provider as it is when ServerHandler is called.
+func ServerHandler() stats.Handler {
+	return otelgrpc.NewServerHandler(options()...)
+}
+
+// ClientHandler starts a span per call and sends its context, and the
+// baggage of the call context, to the server.
+func ClientHandler() stats.Handler {
+	return otelgrpc.NewClientHandler(options()...)
+}
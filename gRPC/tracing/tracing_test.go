@@ -0,0 +1,154 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/mxngocqb/Golang/gRPC/greet/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// tenantServer answers with the tenant of the call baggage.
+type tenantServer struct {
+	pb.UnimplementedGreetServiceServer
+}
+
+func (tenantServer) Greet(ctx context.Context, in *pb.GreetRequest) (*pb.GreetResponse, error) {
+	return &pb.GreetResponse{Result: baggage.FromContext(ctx).Member("tenant").Value()}, nil
+}
+
+func (tenantServer) LongGreet(stream pb.GreetService_LongGreetServer) error {
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.GreetResponse{Result: baggage.FromContext(stream.Context()).Member("tenant").Value()})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// recordSpans installs a tracer provider keeping the ended spans in memory
+// until the end of the test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func dial(t *testing.T, b baggage.Baggage) pb.GreetServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.StatsHandler(ServerHandler()))
+	pb.RegisterGreetServiceServer(s, tenantServer{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(ClientHandler()),
+		grpc.WithChainUnaryInterceptor(UnaryClientBaggage(b)),
+		grpc.WithChainStreamInterceptor(StreamClientBaggage(b)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewGreetServiceClient(conn)
+}
+
+// waitSpans waits for n spans to end, the server ones ending after the
+// client got its answer.
+func waitSpans(t *testing.T, exporter *tracetest.InMemoryExporter, n int) tracetest.SpanStubs {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return len(exporter.GetSpans()) >= n }, time.Second, time.Millisecond)
+	return exporter.GetSpans()
+}
+
+func bySpanKind(spans tracetest.SpanStubs) map[trace.SpanKind]tracetest.SpanStub {
+	m := map[trace.SpanKind]tracetest.SpanStub{}
+	for _, s := range spans {
+		m[s.SpanKind] = s
+	}
+	return m
+}
+
+func TestBaggageReachesTheServer(t *testing.T) {
+	recordSpans(t)
+	b, err := baggage.Parse("tenant=acme")
+	require.NoError(t, err)
+	c := dial(t, b)
+
+	res, err := c.Greet(context.Background(), &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", res.Result)
+
+	// The baggage of the call context wins.
+	override, err := baggage.Parse("tenant=initech")
+	require.NoError(t, err)
+	res, err = c.Greet(baggage.ContextWithBaggage(context.Background(), override), &pb.GreetRequest{FirstName: "Ngoc"})
+	require.NoError(t, err)
+	assert.Equal(t, "initech", res.Result)
+}
+
+func TestStreamMessageEvents(t *testing.T) {
+	exporter := recordSpans(t)
+	b, err := baggage.Parse("tenant=acme")
+	require.NoError(t, err)
+	c := dial(t, b)
+
+	stream, err := c.LongGreet(context.Background())
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Send(&pb.GreetRequest{FirstName: "Ngoc"}))
+	}
+	res, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, "acme", res.Result)
+
+	spans := bySpanKind(waitSpans(t, exporter, 2))
+	client, server := spans[trace.SpanKindClient], spans[trace.SpanKindServer]
+	assert.Equal(t, client.SpanContext.TraceID(), server.SpanContext.TraceID())
+	assert.Equal(t, client.SpanContext.SpanID(), server.Parent.SpanID())
+
+	// 3 requests and a response each side.
+	assert.Len(t, client.Events, 4)
+	assert.Len(t, server.Events, 4)
+	for _, e := range server.Events {
+		assert.Equal(t, "message", e.Name)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	shutdown, err := Setup(context.Background(), "test", Config{Exporter: Stdout})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), "test", Config{Exporter: "jaeger"})
+	assert.ErrorContains(t, err, "unknown trace exporter")
+}